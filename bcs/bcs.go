@@ -0,0 +1,112 @@
+// Package bcs implements the Binary Canonical Serialization used by Sui to encode transactions, objects and
+// events, and the core Sui types in their BCS form.
+//
+// Go values are mapped to BCS by reflection:
+//
+//   - bool, uint8 to uint64 and int8 to int64 are fixed-width little-endian integers, Uint128 and Uint256 the
+//     wider ones. int and uint are not supported, their width depends on the platform.
+//   - string and []byte are a ULEB128 length followed by the bytes, a slice is a ULEB128 length followed by its
+//     elements, an array is its elements without a length.
+//   - a struct is its exported fields in order, a field tagged `bcs:"-"` is skipped.
+//   - a pointer is an option: 0 for nil, or 1 followed by the value.
+//   - a struct implementing Enum is an enum: the ULEB128 index of its only non-nil field followed by the value
+//     the field points to, every field of an enum is a pointer.
+//
+// Types implementing Marshaler and Unmarshaler encode themselves.
+package bcs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrTrailingBytes      = errors.New("bcs: trailing bytes after the value")
+	ErrInvalidBool        = errors.New("bcs: invalid bool")
+	ErrInvalidOption      = errors.New("bcs: invalid option tag")
+	ErrInvalidEnum        = errors.New("bcs: invalid enum variant")
+	ErrInvalidLength      = errors.New("bcs: invalid length")
+	ErrNonCanonicalULEB   = errors.New("bcs: non canonical ULEB128")
+	ErrULEBOverflow       = errors.New("bcs: ULEB128 overflows u32")
+	ErrUnsupportedType    = errors.New("bcs: unsupported type")
+	ErrNotPointer         = errors.New("bcs: Unmarshal needs a non-nil pointer")
+	ErrMaxLengthExceeded  = errors.New("bcs: length exceeds the maximum sequence length")
+	ErrInvalidUTF8        = errors.New("bcs: string is not valid UTF-8")
+	ErrEnumVariantNotSet  = errors.New("bcs: enum has no variant set")
+	ErrEnumVariantsNotOne = errors.New("bcs: enum has several variants set")
+)
+
+// MaxSequenceLength is the maximum length of a BCS sequence.
+const MaxSequenceLength = 1<<31 - 1
+
+// Marshaler is implemented by the types encoding themselves.
+type Marshaler interface {
+	MarshalBCS(e *Encoder) error
+}
+
+// Unmarshaler is implemented by the types decoding themselves.
+type Unmarshaler interface {
+	UnmarshalBCS(d *Decoder) error
+}
+
+// Enum marks a struct as an enum, see the package documentation.
+type Enum interface {
+	IsBcsEnum()
+}
+
+// Marshal returns the BCS encoding of v. A pointer v is encoded as the value it points to.
+func Marshal(v interface{}) ([]byte, error) {
+	e := NewEncoder()
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes data into the value v points to, data must hold exactly one value.
+func Unmarshal(data []byte, v interface{}) error {
+	d := NewDecoder(data)
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+	if d.Remaining() > 0 {
+		return fmt.Errorf("%w: %d bytes", ErrTrailingBytes, d.Remaining())
+	}
+	return nil
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	enumType        = reflect.TypeOf((*Enum)(nil)).Elem()
+)
+
+// fieldCache holds the encoded fields of the struct types by type.
+var fieldCache sync.Map
+
+// fields returns the indexes of the fields of the struct type t that are encoded.
+func fields(t reflect.Type) []int {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]int)
+	}
+	var indexes []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if tag, _, _ := strings.Cut(f.Tag.Get("bcs"), ","); tag == "-" {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	fieldCache.Store(t, indexes)
+	return indexes
+}
+
+func isEnum(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && (t.Implements(enumType) || reflect.PointerTo(t).Implements(enumType))
+}
@@ -0,0 +1,248 @@
+package bcs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type wrapper uint8
+
+type bar struct {
+	A uint64
+	B []uint8
+	C wrapper
+	D uint32
+}
+
+type foo struct {
+	A       uint64
+	B       []uint8
+	C       bar
+	E       bool
+	Skipped string `bcs:"-"`
+	F       *uint16
+	G       []string
+}
+
+type option struct {
+	V *uint8
+}
+
+type variant struct {
+	Variant0 *uint16
+	Variant1 *uint8
+	Variant2 *string
+}
+
+func (variant) IsBcsEnum() {}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestULEB128(t *testing.T) {
+	vectors := map[uint64]string{
+		0:         "00",
+		1:         "01",
+		127:       "7f",
+		128:       "8001",
+		300:       "ac02",
+		16383:     "ff7f",
+		16384:     "808001",
+		1<<32 - 1: "ffffffff0f",
+	}
+	for v, want := range vectors {
+		e := NewEncoder()
+		e.WriteULEB128(v)
+		if got := hex.EncodeToString(e.Bytes()); got != want {
+			t.Errorf("ULEB128 of %d is %s, expected %s", v, got, want)
+		}
+		if got, err := NewDecoder(unhex(want)).ReadULEB128(); err != nil || got != v {
+			t.Errorf("decoded %s as %d, %v", want, got, err)
+		}
+	}
+
+	for in, want := range map[string]error{
+		"8000":         ErrNonCanonicalULEB,
+		"ff00":         ErrNonCanonicalULEB,
+		"8080808010":   ErrULEBOverflow,
+		"ffffffffffff": ErrULEBOverflow,
+		"80":           io.ErrUnexpectedEOF,
+	} {
+		if _, err := NewDecoder(unhex(in)).ReadULEB128(); !errors.Is(err, want) {
+			t.Errorf("expected %v decoding %s, got %v", want, in, err)
+		}
+	}
+}
+
+func TestKnownVectors(t *testing.T) {
+	vectors := []struct {
+		name  string
+		value interface{}
+		bcs   string
+	}{
+		{"bool", true, "01"},
+		{"u8", uint8(1), "01"},
+		{"u16", uint16(4660), "3412"},
+		{"u32", uint32(305419896), "78563412"},
+		{"u64", uint64(1311768467750121216), "00efcdab78563412"},
+		{"i8", int8(-1), "ff"},
+		{"i32", int32(-2), "feffffff"},
+		{"u128", Uint128FromUint64(1311768467750121216), "00efcdab78563412 0000000000000000"},
+		{"u256", Uint256FromUint64(1), "01" + strings.Repeat("00", 31)},
+		{"option none", option{}, "00"},
+		{"option some", option{V: ptr(uint8(8))}, "0108"},
+		{"vector", []uint8{1, 2, 3}, "03010203"},
+		{"vector of u16", []uint16{1, 2}, "0201000200"},
+		{"array", [3]uint16{1, 2, 3}, "010002000300"},
+		{"string", "çå∞≠¢õß∂ƒ∫", "18c3a7c3a5e2889ee289a0c2a2c3b5c39fe28882c692e288ab"},
+		{"struct", bar{A: 100, B: []uint8{0, 1, 2, 3, 4, 5, 6, 7, 8}, C: 5, D: 99}, "6400000000000000 09000102030405060708 05 63000000"},
+		{"nested struct", foo{
+			A: 1,
+			B: []uint8{2},
+			C: bar{A: 100, B: []uint8{}, C: 5, D: 99},
+			E: true,
+			F: ptr(uint16(1)),
+			G: []string{"a", "bc"},
+		}, "0100000000000000 0102 6400000000000000 00 05 63000000 01 010100 02 0161 026263"},
+		{"enum variant 0", variant{Variant0: ptr(uint16(8000))}, "00401f"},
+		{"enum variant 1", variant{Variant1: ptr(uint8(255))}, "01ff"},
+		{"enum variant 2", variant{Variant2: ptr("e")}, "020165"},
+	}
+	for _, v := range vectors {
+		got, err := Marshal(v.value)
+		if err != nil {
+			t.Errorf("%s: %v", v.name, err)
+			continue
+		}
+		if want := unhex(v.bcs); !bytes.Equal(got, want) {
+			t.Errorf("%s: encoded as %x, expected %x", v.name, got, want)
+			continue
+		}
+		decoded := reflect.New(reflect.TypeOf(v.value))
+		if err := Unmarshal(got, decoded.Interface()); err != nil {
+			t.Errorf("%s: %v", v.name, err)
+			continue
+		}
+		if again, _ := Marshal(decoded.Interface()); !bytes.Equal(again, got) {
+			t.Errorf("%s: round trip encoded as %x", v.name, again)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var b bool
+	if err := Unmarshal([]byte{2}, &b); !errors.Is(err, ErrInvalidBool) {
+		t.Errorf("expected ErrInvalidBool, got %v", err)
+	}
+	var o *uint8
+	if err := Unmarshal([]byte{2, 1}, &o); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+	var e variant
+	if err := Unmarshal([]byte{3}, &e); !errors.Is(err, ErrInvalidEnum) {
+		t.Errorf("expected ErrInvalidEnum, got %v", err)
+	}
+	var n uint32
+	if err := Unmarshal([]byte{1, 0, 0, 0, 0}, &n); !errors.Is(err, ErrTrailingBytes) {
+		t.Errorf("expected ErrTrailingBytes, got %v", err)
+	}
+	var s []uint64
+	if err := Unmarshal([]byte{0xff, 0xff, 0xff, 0xff, 0x07, 1}, &s); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	var str string
+	if err := Unmarshal([]byte{1, 0xff}, &str); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("expected ErrInvalidUTF8, got %v", err)
+	}
+	if _, err := Marshal(variant{}); !errors.Is(err, ErrEnumVariantNotSet) {
+		t.Errorf("expected ErrEnumVariantNotSet, got %v", err)
+	}
+	if _, err := Marshal(map[string]int{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+	if _, err := NewUint128(new(big.Int).Lsh(big.NewInt(1), 128)); err == nil {
+		t.Error("expected 2^128 to overflow u128")
+	}
+}
+
+func TestSuiTypes(t *testing.T) {
+	sui := MustParseSuiAddress("0x2")
+	if sui.String() != "0x"+strings.Repeat("0", 63)+"2" || sui.ShortString() != "0x2" {
+		t.Errorf("unexpected address %s, %s", sui, sui.ShortString())
+	}
+	if _, err := ParseSuiAddress("0x" + strings.Repeat("1", 65)); err == nil {
+		t.Error("expected an address of 65 hex characters to be rejected")
+	}
+
+	digest, err := ParseDigest("8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd")
+	if err != nil || digest.String() != "8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd" {
+		t.Fatalf("unexpected digest %s, %v", digest, err)
+	}
+	ref := ObjectRef{ObjectID: MustParseSuiAddress("0x5"), Version: 41, Digest: digest}
+	got, err := Marshal(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(unhex(strings.Repeat("00", 31)+"05"+"2900000000000000"+"20"), digest[:]...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("object ref encoded as %x, expected %x", got, want)
+	}
+	var decoded ObjectRef
+	if err := Unmarshal(got, &decoded); err != nil || decoded != ref {
+		t.Errorf("unexpected object ref %+v, %v", decoded, err)
+	}
+
+	// 0x2::sui::SUI is the variant 7, the address, the module, the name and no type parameters
+	tag, err := ParseTypeTag("0x2::sui::SUI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = Marshal(tag)
+	want = unhex("07" + strings.Repeat("00", 31) + "02" + "03737569" + "03535549" + "00")
+	if !bytes.Equal(got, want) {
+		t.Errorf("0x2::sui::SUI encoded as %x, expected %x", got, want)
+	}
+
+	for _, s := range []string{
+		"u8",
+		"vector<u256>",
+		"0x2::coin::Coin<0x2::sui::SUI>",
+		"0x3::staking_pool::StakedSui",
+		"0x2::dynamic_field::Field<vector<u8>, 0x2::table::Table<address, vector<0x2::object::ID>>>",
+	} {
+		tag, err := ParseTypeTag(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		b, err := Marshal(tag)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		var decoded TypeTag
+		if err := Unmarshal(b, &decoded); err != nil || decoded.String() != s || !reflect.DeepEqual(decoded, tag) {
+			t.Errorf("%s: decoded as %s, %v", s, decoded, err)
+		}
+	}
+	for _, s := range []string{"", "u9", "vector<u8", "0x2::sui", "0x2::sui::SUI<>", "0x2::sui::SUI u8"} {
+		if _, err := ParseTypeTag(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
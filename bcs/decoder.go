@@ -0,0 +1,294 @@
+package bcs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"unicode/utf8"
+)
+
+// Decoder reads BCS encoded values from a byte slice.
+type Decoder struct {
+	data []byte
+	off  int
+}
+
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Remaining returns the number of bytes not read yet.
+func (d *Decoder) Remaining() int {
+	return len(d.data) - d.off
+}
+
+// ReadFixedBytes reads n bytes. The returned slice aliases the data of the decoder.
+func (d *Decoder) ReadFixedBytes(n int) ([]byte, error) {
+	if n < 0 || n > d.Remaining() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+func (d *Decoder) ReadU8() (uint8, error) {
+	b, err := d.ReadFixedBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *Decoder) ReadU16() (uint16, error) {
+	b, err := d.ReadFixedBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (d *Decoder) ReadU32() (uint32, error) {
+	b, err := d.ReadFixedBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (d *Decoder) ReadU64() (uint64, error) {
+	b, err := d.ReadFixedBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (d *Decoder) ReadBool() (bool, error) {
+	b, err := d.ReadU8()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: %d", ErrInvalidBool, b)
+	}
+}
+
+// ReadULEB128 reads an unsigned LEB128. BCS only uses them for lengths and enum variants, so values above u32
+// and encodings with trailing zero bytes are rejected.
+func (d *Decoder) ReadULEB128() (uint64, error) {
+	var v uint64
+	for shift := 0; shift < 35; shift += 7 {
+		b, err := d.ReadU8()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if b == 0 && shift > 0 {
+				return 0, ErrNonCanonicalULEB
+			}
+			if v > 1<<32-1 {
+				return 0, ErrULEBOverflow
+			}
+			return v, nil
+		}
+	}
+	return 0, ErrULEBOverflow
+}
+
+// ReadLength reads the length of a sequence.
+func (d *Decoder) ReadLength() (int, error) {
+	n, err := d.ReadULEB128()
+	if err != nil {
+		return 0, err
+	}
+	if n > MaxSequenceLength {
+		return 0, ErrMaxLengthExceeded
+	}
+	return int(n), nil
+}
+
+// ReadBytes reads a vector<u8>. The returned slice aliases the data of the decoder.
+func (d *Decoder) ReadBytes() ([]byte, error) {
+	n, err := d.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	return d.ReadFixedBytes(n)
+}
+
+func (d *Decoder) ReadString() (string, error) {
+	b, err := d.ReadBytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", ErrInvalidUTF8
+	}
+	return string(b), nil
+}
+
+// Decode decodes the next value into the value v points to.
+func (d *Decoder) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrNotPointer
+	}
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalBCS(d)
+	}
+	return d.decode(rv.Elem())
+}
+
+func (d *Decoder) decode(v reflect.Value) error {
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalBCS(d)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := d.ReadBool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Uint8, reflect.Int8:
+		n, err := d.ReadU8()
+		if err != nil {
+			return err
+		}
+		setUint(v, uint64(n))
+	case reflect.Uint16, reflect.Int16:
+		n, err := d.ReadU16()
+		if err != nil {
+			return err
+		}
+		setUint(v, uint64(n))
+	case reflect.Uint32, reflect.Int32:
+		n, err := d.ReadU32()
+		if err != nil {
+			return err
+		}
+		setUint(v, uint64(n))
+	case reflect.Uint64, reflect.Int64:
+		n, err := d.ReadU64()
+		if err != nil {
+			return err
+		}
+		setUint(v, n)
+	case reflect.String:
+		s, err := d.ReadString()
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Slice:
+		n, err := d.ReadLength()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			v.SetZero()
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := d.ReadFixedBytes(n)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		// every element takes a byte at least, unless it is empty, so the remaining bytes bound the allocation
+		v.Set(reflect.MakeSlice(v.Type(), 0, min(n, d.Remaining())))
+		for i := 0; i < n; i++ {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(elem); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, elem))
+		}
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := d.ReadFixedBytes(v.Len())
+			if err != nil {
+				return err
+			}
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Pointer:
+		tag, err := d.ReadU8()
+		if err != nil {
+			return err
+		}
+		switch tag {
+		case 0:
+			v.SetZero()
+		case 1:
+			v.Set(reflect.New(v.Type().Elem()))
+			return d.decode(v.Elem())
+		default:
+			return fmt.Errorf("%w: %d", ErrInvalidOption, tag)
+		}
+	case reflect.Struct:
+		if isEnum(v.Type()) {
+			return d.decodeEnum(v)
+		}
+		for _, i := range fields(v.Type()) {
+			if err := d.decode(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type())
+	}
+	return nil
+}
+
+// setUint sets the unsigned or two's complement signed integer v to n.
+func setUint(v reflect.Value, n uint64) {
+	switch v.Kind() {
+	case reflect.Int8:
+		v.SetInt(int64(int8(n)))
+	case reflect.Int16:
+		v.SetInt(int64(int16(n)))
+	case reflect.Int32:
+		v.SetInt(int64(int32(n)))
+	case reflect.Int64:
+		v.SetInt(int64(n))
+	default:
+		v.SetUint(n)
+	}
+}
+
+// decodeEnum reads the index of the variant and sets the matching field, the others are set to nil.
+func (d *Decoder) decodeEnum(v reflect.Value) error {
+	variant, err := d.ReadULEB128()
+	if err != nil {
+		return err
+	}
+	indexes := fields(v.Type())
+	if variant >= uint64(len(indexes)) {
+		return fmt.Errorf("%w: %d of %s", ErrInvalidEnum, variant, v.Type())
+	}
+	v.SetZero()
+	f := v.Field(indexes[variant])
+	if f.Kind() != reflect.Pointer {
+		return fmt.Errorf("%w: variant %s of %s is not a pointer", ErrUnsupportedType, v.Type().Field(indexes[variant]).Name, v.Type())
+	}
+	f.Set(reflect.New(f.Type().Elem()))
+	return d.decode(f.Elem())
+}
@@ -0,0 +1,183 @@
+package bcs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// Encoder appends the BCS encoding of values to a buffer.
+type Encoder struct {
+	buf []byte
+}
+
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Bytes returns the encoded bytes.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+func (e *Encoder) WriteU8(v uint8) {
+	e.buf = append(e.buf, v)
+}
+
+func (e *Encoder) WriteU16(v uint16) {
+	e.buf = binary.LittleEndian.AppendUint16(e.buf, v)
+}
+
+func (e *Encoder) WriteU32(v uint32) {
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+func (e *Encoder) WriteU64(v uint64) {
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, v)
+}
+
+func (e *Encoder) WriteBool(v bool) {
+	if v {
+		e.WriteU8(1)
+	} else {
+		e.WriteU8(0)
+	}
+}
+
+// WriteULEB128 writes v as an unsigned LEB128, the encoding of lengths and enum variants.
+func (e *Encoder) WriteULEB128(v uint64) {
+	for v >= 0x80 {
+		e.buf = append(e.buf, byte(v)|0x80)
+		v >>= 7
+	}
+	e.buf = append(e.buf, byte(v))
+}
+
+// WriteFixedBytes writes b without its length.
+func (e *Encoder) WriteFixedBytes(b []byte) {
+	e.buf = append(e.buf, b...)
+}
+
+// WriteBytes writes b preceded by its length, the encoding of a vector<u8>.
+func (e *Encoder) WriteBytes(b []byte) {
+	e.WriteULEB128(uint64(len(b)))
+	e.WriteFixedBytes(b)
+}
+
+func (e *Encoder) WriteString(s string) {
+	e.WriteULEB128(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// Encode appends the BCS encoding of v. A pointer v is encoded as the value it points to.
+func (e *Encoder) Encode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() && !rv.Type().Implements(marshalerType) {
+		rv = rv.Elem()
+	}
+	return e.encode(rv)
+}
+
+func (e *Encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		return fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	if v.Type().Implements(marshalerType) && (v.Kind() != reflect.Pointer || !v.IsNil()) {
+		return v.Interface().(Marshaler).MarshalBCS(e)
+	}
+	if v.Kind() != reflect.Pointer && reflect.PointerTo(v.Type()).Implements(marshalerType) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(Marshaler).MarshalBCS(e)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		e.WriteBool(v.Bool())
+	case reflect.Uint8:
+		e.WriteU8(uint8(v.Uint()))
+	case reflect.Uint16:
+		e.WriteU16(uint16(v.Uint()))
+	case reflect.Uint32:
+		e.WriteU32(uint32(v.Uint()))
+	case reflect.Uint64:
+		e.WriteU64(v.Uint())
+	case reflect.Int8:
+		e.WriteU8(uint8(v.Int()))
+	case reflect.Int16:
+		e.WriteU16(uint16(v.Int()))
+	case reflect.Int32:
+		e.WriteU32(uint32(v.Int()))
+	case reflect.Int64:
+		e.WriteU64(uint64(v.Int()))
+	case reflect.String:
+		e.WriteString(v.String())
+	case reflect.Slice:
+		if v.Len() > MaxSequenceLength {
+			return ErrMaxLengthExceeded
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.WriteBytes(v.Bytes())
+			return nil
+		}
+		e.WriteULEB128(uint64(v.Len()))
+		return e.encodeElems(v)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 && v.CanAddr() {
+			e.WriteFixedBytes(v.Bytes())
+			return nil
+		}
+		return e.encodeElems(v)
+	case reflect.Pointer:
+		if v.IsNil() {
+			e.WriteU8(0)
+			return nil
+		}
+		e.WriteU8(1)
+		return e.encode(v.Elem())
+	case reflect.Struct:
+		if isEnum(v.Type()) {
+			return e.encodeEnum(v)
+		}
+		for _, i := range fields(v.Type()) {
+			if err := e.encode(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type())
+	}
+	return nil
+}
+
+func (e *Encoder) encodeElems(v reflect.Value) error {
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeEnum writes the index of the variant set and its value.
+func (e *Encoder) encodeEnum(v reflect.Value) error {
+	variant := -1
+	for i, index := range fields(v.Type()) {
+		f := v.Field(index)
+		if f.Kind() != reflect.Pointer {
+			return fmt.Errorf("%w: variant %s of %s is not a pointer", ErrUnsupportedType, v.Type().Field(index).Name, v.Type())
+		}
+		if f.IsNil() {
+			continue
+		}
+		if variant >= 0 {
+			return fmt.Errorf("%w: %s", ErrEnumVariantsNotOne, v.Type())
+		}
+		variant = i
+	}
+	if variant < 0 {
+		return fmt.Errorf("%w: %s", ErrEnumVariantNotSet, v.Type())
+	}
+	e.WriteULEB128(uint64(variant))
+	return e.encode(v.Field(fields(v.Type())[variant]).Elem())
+}
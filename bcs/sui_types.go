@@ -0,0 +1,156 @@
+package bcs
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/yasir7ca/sui-go-sdk/utils"
+)
+
+// SuiAddressLength is the length of an address and of an object id.
+const SuiAddressLength = 32
+
+// DigestLength is the length of the digests.
+const DigestLength = 32
+
+// SuiAddress is an account address, encoded as its 32 bytes.
+type SuiAddress [SuiAddressLength]byte
+
+// ObjectID is the id of an object, an address.
+type ObjectID = SuiAddress
+
+// SequenceNumber is the version of an object.
+type SequenceNumber = uint64
+
+// ParseSuiAddress parses a hex address, with or without its 0x prefix and its leading zeros, "0x2" is the address
+// of the Sui framework.
+func ParseSuiAddress(s string) (SuiAddress, error) {
+	var addr SuiAddress
+	h := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if h == "" || len(h) > 2*SuiAddressLength {
+		return addr, fmt.Errorf("invalid address %q", s)
+	}
+	if len(h)%2 == 1 {
+		h = "0" + h
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return addr, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	copy(addr[SuiAddressLength-len(b):], b)
+	return addr, nil
+}
+
+// MustParseSuiAddress is ParseSuiAddress panicking on error, for the addresses known at compile time.
+func MustParseSuiAddress(s string) SuiAddress {
+	addr, err := ParseSuiAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+// String returns the 0x prefixed 64 hex characters of the address.
+func (a SuiAddress) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// ShortString returns the address without its leading zeros, as in the type names returned by the nodes: "0x2".
+func (a SuiAddress) ShortString() string {
+	h := strings.TrimLeft(hex.EncodeToString(a[:]), "0")
+	if h == "" {
+		h = "0"
+	}
+	return "0x" + h
+}
+
+func (a SuiAddress) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *SuiAddress) UnmarshalText(text []byte) error {
+	addr, err := ParseSuiAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = addr
+	return nil
+}
+
+// Digest is a 32 bytes digest, of a transaction, an object or a checkpoint. Unlike an address it is encoded as a
+// vector<u8>, with its length.
+type Digest [DigestLength]byte
+
+type ObjectDigest = Digest
+
+type TransactionDigest = Digest
+
+// ParseDigest parses a base58 digest.
+func ParseDigest(s string) (Digest, error) {
+	var d Digest
+	b, err := utils.Base58Decode(s)
+	if err != nil {
+		return d, fmt.Errorf("invalid digest %q: %w", s, err)
+	}
+	if len(b) != DigestLength {
+		return d, fmt.Errorf("invalid digest %q: %d bytes", s, len(b))
+	}
+	copy(d[:], b)
+	return d, nil
+}
+
+// String returns the base58 digest.
+func (d Digest) String() string {
+	return utils.Base58Encode(d[:])
+}
+
+func (d Digest) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Digest) UnmarshalText(text []byte) error {
+	digest, err := ParseDigest(string(text))
+	if err != nil {
+		return err
+	}
+	*d = digest
+	return nil
+}
+
+func (d Digest) MarshalBCS(e *Encoder) error {
+	e.WriteBytes(d[:])
+	return nil
+}
+
+func (d *Digest) UnmarshalBCS(dec *Decoder) error {
+	b, err := dec.ReadBytes()
+	if err != nil {
+		return err
+	}
+	if len(b) != DigestLength {
+		return fmt.Errorf("%w: digest of %d bytes", ErrInvalidLength, len(b))
+	}
+	copy(d[:], b)
+	return nil
+}
+
+// ObjectRef references a version of an object.
+type ObjectRef struct {
+	ObjectID ObjectID
+	Version  SequenceNumber
+	Digest   ObjectDigest
+}
+
+// ParseObjectRef returns the reference to the version of an object as returned by the nodes.
+func ParseObjectRef(objectID string, version uint64, digest string) (ObjectRef, error) {
+	id, err := ParseSuiAddress(objectID)
+	if err != nil {
+		return ObjectRef{}, err
+	}
+	d, err := ParseDigest(digest)
+	if err != nil {
+		return ObjectRef{}, err
+	}
+	return ObjectRef{ObjectID: id, Version: version, Digest: d}, nil
+}
@@ -0,0 +1,210 @@
+package bcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeTag is the type of a Move value, an enum whose variants are in the order of Sui.
+type TypeTag struct {
+	Bool    *struct{}
+	U8      *struct{}
+	U64     *struct{}
+	U128    *struct{}
+	Address *struct{}
+	Signer  *struct{}
+	Vector  *TypeTag
+	Struct  *StructTag
+	U16     *struct{}
+	U32     *struct{}
+	U256    *struct{}
+}
+
+func (TypeTag) IsBcsEnum() {}
+
+// StructTag is the type of a Move struct: "0x2::coin::Coin<0x2::sui::SUI>".
+type StructTag struct {
+	Address    SuiAddress
+	Module     string
+	Name       string
+	TypeParams []TypeTag
+}
+
+// ParseTypeTag parses a Move type: a primitive such as "u64", "vector<u8>" or a struct such as
+// "0x2::coin::Coin<0x2::sui::SUI>".
+func ParseTypeTag(s string) (TypeTag, error) {
+	p := typeParser{s: s}
+	tag, err := p.typeTag()
+	if err != nil {
+		return TypeTag{}, fmt.Errorf("invalid type %q: %w", s, err)
+	}
+	if p.skipSpaces(); p.pos != len(p.s) {
+		return TypeTag{}, fmt.Errorf("invalid type %q: unexpected %q", s, p.s[p.pos:])
+	}
+	return tag, nil
+}
+
+// ParseStructTag parses the type of a Move struct, such as "0x2::sui::SUI".
+func ParseStructTag(s string) (StructTag, error) {
+	tag, err := ParseTypeTag(s)
+	if err != nil {
+		return StructTag{}, err
+	}
+	if tag.Struct == nil {
+		return StructTag{}, fmt.Errorf("invalid struct type %q", s)
+	}
+	return *tag.Struct, nil
+}
+
+// String returns the type in the form the nodes return it, with the addresses without their leading zeros.
+func (t TypeTag) String() string {
+	switch {
+	case t.Bool != nil:
+		return "bool"
+	case t.U8 != nil:
+		return "u8"
+	case t.U16 != nil:
+		return "u16"
+	case t.U32 != nil:
+		return "u32"
+	case t.U64 != nil:
+		return "u64"
+	case t.U128 != nil:
+		return "u128"
+	case t.U256 != nil:
+		return "u256"
+	case t.Address != nil:
+		return "address"
+	case t.Signer != nil:
+		return "signer"
+	case t.Vector != nil:
+		return "vector<" + t.Vector.String() + ">"
+	case t.Struct != nil:
+		return t.Struct.String()
+	default:
+		return ""
+	}
+}
+
+func (t StructTag) String() string {
+	s := t.Address.ShortString() + "::" + t.Module + "::" + t.Name
+	if len(t.TypeParams) == 0 {
+		return s
+	}
+	params := make([]string, len(t.TypeParams))
+	for i, param := range t.TypeParams {
+		params[i] = param.String()
+	}
+	return s + "<" + strings.Join(params, ", ") + ">"
+}
+
+var primitiveTypeTags = map[string]func() TypeTag{
+	"bool":    func() TypeTag { return TypeTag{Bool: &struct{}{}} },
+	"u8":      func() TypeTag { return TypeTag{U8: &struct{}{}} },
+	"u16":     func() TypeTag { return TypeTag{U16: &struct{}{}} },
+	"u32":     func() TypeTag { return TypeTag{U32: &struct{}{}} },
+	"u64":     func() TypeTag { return TypeTag{U64: &struct{}{}} },
+	"u128":    func() TypeTag { return TypeTag{U128: &struct{}{}} },
+	"u256":    func() TypeTag { return TypeTag{U256: &struct{}{}} },
+	"address": func() TypeTag { return TypeTag{Address: &struct{}{}} },
+	"signer":  func() TypeTag { return TypeTag{Signer: &struct{}{}} },
+}
+
+// typeParser is a recursive descent parser of Move types.
+type typeParser struct {
+	s   string
+	pos int
+}
+
+func (p *typeParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// ident reads an identifier, or an address.
+func (p *typeParser) ident() (string, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("expected an identifier at %d", start)
+	}
+	return p.s[start:p.pos], nil
+}
+
+func (p *typeParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *typeParser) expect(token string) error {
+	if !p.consume(token) {
+		return fmt.Errorf("expected %q at %d", token, p.pos)
+	}
+	return nil
+}
+
+func (p *typeParser) typeTag() (TypeTag, error) {
+	name, err := p.ident()
+	if err != nil {
+		return TypeTag{}, err
+	}
+	if name == "vector" {
+		if err := p.expect("<"); err != nil {
+			return TypeTag{}, err
+		}
+		elem, err := p.typeTag()
+		if err != nil {
+			return TypeTag{}, err
+		}
+		return TypeTag{Vector: &elem}, p.expect(">")
+	}
+	if primitive, ok := primitiveTypeTags[name]; ok {
+		return primitive(), nil
+	}
+
+	addr, err := ParseSuiAddress(name)
+	if err != nil {
+		return TypeTag{}, err
+	}
+	tag := StructTag{Address: addr}
+	if err := p.expect("::"); err != nil {
+		return TypeTag{}, err
+	}
+	if tag.Module, err = p.ident(); err != nil {
+		return TypeTag{}, err
+	}
+	if err := p.expect("::"); err != nil {
+		return TypeTag{}, err
+	}
+	if tag.Name, err = p.ident(); err != nil {
+		return TypeTag{}, err
+	}
+	if p.consume("<") {
+		for {
+			param, err := p.typeTag()
+			if err != nil {
+				return TypeTag{}, err
+			}
+			tag.TypeParams = append(tag.TypeParams, param)
+			if !p.consume(",") {
+				break
+			}
+		}
+		if err := p.expect(">"); err != nil {
+			return TypeTag{}, err
+		}
+	}
+	return TypeTag{Struct: &tag}, nil
+}
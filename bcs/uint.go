@@ -0,0 +1,68 @@
+package bcs
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// Uint128 is a u128, its little-endian bytes.
+type Uint128 [16]byte
+
+// Uint256 is a u256, its little-endian bytes.
+type Uint256 [32]byte
+
+// NewUint128 returns n as a Uint128, n must fit in 128 bits.
+func NewUint128(n *big.Int) (Uint128, error) {
+	var u Uint128
+	return u, putBig(u[:], n)
+}
+
+// NewUint256 returns n as a Uint256, n must fit in 256 bits.
+func NewUint256(n *big.Int) (Uint256, error) {
+	var u Uint256
+	return u, putBig(u[:], n)
+}
+
+// Uint128FromUint64 returns n as a Uint128.
+func Uint128FromUint64(n uint64) Uint128 {
+	u, _ := NewUint128(new(big.Int).SetUint64(n))
+	return u
+}
+
+// Uint256FromUint64 returns n as a Uint256.
+func Uint256FromUint64(n uint64) Uint256 {
+	u, _ := NewUint256(new(big.Int).SetUint64(n))
+	return u
+}
+
+func (u Uint128) Big() *big.Int {
+	return leToBig(u[:])
+}
+
+func (u Uint256) Big() *big.Int {
+	return leToBig(u[:])
+}
+
+func (u Uint128) String() string {
+	return u.Big().String()
+}
+
+func (u Uint256) String() string {
+	return u.Big().String()
+}
+
+func putBig(dst []byte, n *big.Int) error {
+	if n.Sign() < 0 || n.BitLen() > len(dst)*8 {
+		return fmt.Errorf("%s doesn't fit in u%d", n, len(dst)*8)
+	}
+	n.FillBytes(dst)
+	slices.Reverse(dst)
+	return nil
+}
+
+func leToBig(le []byte) *big.Int {
+	be := slices.Clone(le)
+	slices.Reverse(be)
+	return new(big.Int).SetBytes(be)
+}
@@ -0,0 +1,376 @@
+// Package cassette records the JSON-RPC calls sent over HTTP to a fixture file and replays them
+// without network access.
+//
+// A Cassette is an http.RoundTripper, install it on the client under test:
+//
+//	c, err := cassette.Load("testdata/objects.json", cassette.ModeFromEnv())
+//	defer c.Save()
+//	cli := sui.NewSuiClient(constant.SuiTestnetEndpoint, sui.WithHTTPClient(c.Client()))
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInteractionNotFound is returned in replay mode for a call missing from the cassette.
+	ErrInteractionNotFound = errors.New("cassette: no recorded interaction for the call")
+)
+
+// ModeEnv is the environment variable read by ModeFromEnv.
+const ModeEnv = "SUI_CASSETTE_MODE"
+
+// Mode selects whether a Cassette talks to the network.
+type Mode int
+
+const (
+	// ModeReplay serves every call from the cassette and fails calls that were not recorded.
+	ModeReplay Mode = iota
+	// ModeRecord sends every call to the network and records it, replacing the previous recordings.
+	ModeRecord
+	// ModeReplayOrRecord replays the recorded calls and records the missing ones.
+	ModeReplayOrRecord
+)
+
+// ModeFromEnv returns the mode named by $SUI_CASSETTE_MODE: `record`, `replay` or `auto`.
+// ModeReplay is returned if the variable is empty or unknown, so that tests never reach the network by accident.
+func ModeFromEnv() Mode {
+	switch strings.ToLower(os.Getenv(ModeEnv)) {
+	case "record":
+		return ModeRecord
+	case "auto":
+		return ModeReplayOrRecord
+	default:
+		return ModeReplay
+	}
+}
+
+// Interaction is a recorded JSON-RPC call. Request ids are not recorded, a replayed response takes
+// the id of the request it answers.
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+type file struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Cassette is an http.RoundTripper recording and replaying JSON-RPC calls, batches included.
+//
+// Calls are matched by method and normalized params. A call made several times with the same
+// arguments replays its recordings in order, then keeps replaying the last one.
+type Cassette struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	byKey        map[string][]*Interaction
+	played       map[string]int
+	dirty        bool
+}
+
+// Load opens the cassette at path, a missing file is an empty cassette.
+func Load(path string, mode Mode) (*Cassette, error) {
+	c := &Cassette{
+		path:   path,
+		mode:   mode,
+		next:   http.DefaultTransport,
+		byKey:  make(map[string][]*Interaction),
+		played: make(map[string]int),
+	}
+	if mode == ModeRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	for _, i := range f.Interactions {
+		if err := c.add(i); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// SetTransport replaces the transport used to record calls, http.DefaultTransport by default.
+func (c *Cassette) SetTransport(next http.RoundTripper) {
+	c.next = next
+}
+
+// Client returns an HTTP client sending its requests through the cassette.
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// Interactions returns the recorded calls.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	interactions := make([]Interaction, 0, len(c.interactions))
+	for _, i := range c.interactions {
+		interactions = append(interactions, *i)
+	}
+	return interactions
+}
+
+// Save writes the cassette to its file if new calls were recorded.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(file{Interactions: c.interactions}); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	reqs, batch, err := parseRequests(body)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+
+	rsps, missing, err := c.replay(reqs)
+	if err != nil {
+		return nil, err
+	}
+	if missing != nil {
+		if c.mode == ModeReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, missing.Method, missing.Params)
+		}
+		return c.record(req, body, reqs)
+	}
+	return newResponse(req, rsps, batch)
+}
+
+// replay answers the calls from the cassette, missing is the first of them that was not recorded.
+func (c *Cassette) replay(reqs []rpcRequest) ([]rpcResponse, *rpcRequest, error) {
+	if c.mode == ModeRecord {
+		return nil, &reqs[0], nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, len(reqs))
+	for n, r := range reqs {
+		key, err := interactionKey(r.Method, r.Params)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(c.byKey[key]) == 0 {
+			return nil, &reqs[n], nil
+		}
+		keys[n] = key
+	}
+
+	rsps := make([]rpcResponse, len(reqs))
+	for n, key := range keys {
+		recorded := c.byKey[key]
+		i := recorded[len(recorded)-1]
+		if played := c.played[key]; played < len(recorded) {
+			i = recorded[played]
+			c.played[key] = played + 1
+		}
+		rsps[n] = rpcResponse{Version: "2.0", ID: reqs[n].ID, Result: i.Result, Error: i.Error}
+	}
+	return rsps, nil, nil
+}
+
+// record sends the request to the network and records the calls it answered.
+func (c *Cassette) record(req *http.Request, body []byte, reqs []rpcRequest) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	resp, err := c.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// HTTP failures are transient by nature and not worth replaying
+		return resp, nil
+	}
+
+	var rsps []rpcResponse
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &rsps)
+	} else {
+		var rsp rpcResponse
+		err = json.Unmarshal(trimmed, &rsp)
+		rsps = append(rsps, rsp)
+	}
+	if err != nil {
+		return resp, nil
+	}
+
+	byID := make(map[string]rpcResponse, len(rsps))
+	for _, rsp := range rsps {
+		byID[string(rsp.ID)] = rsp
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range reqs {
+		rsp, ok := byID[string(r.ID)]
+		if !ok {
+			continue
+		}
+		i := &Interaction{Method: r.Method, Params: r.Params, Result: rsp.Result, Error: rsp.Error}
+		if err := c.add(i); err != nil {
+			return nil, err
+		}
+		key, _ := interactionKey(i.Method, i.Params)
+		// a recorded call is not replayed again within the same run
+		c.played[key] = len(c.byKey[key])
+		c.dirty = true
+	}
+	return resp, nil
+}
+
+func (c *Cassette) add(i *Interaction) error {
+	params, err := normalize(i.Params)
+	if err != nil {
+		return err
+	}
+	i.Params = params
+	key := i.Method + " " + string(params)
+	c.interactions = append(c.interactions, i)
+	c.byKey[key] = append(c.byKey[key], i)
+	return nil
+}
+
+func parseRequests(body []byte) ([]rpcRequest, bool, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []rpcRequest
+		if err := json.Unmarshal(trimmed, &reqs); err != nil {
+			return nil, false, err
+		}
+		if len(reqs) == 0 {
+			return nil, false, errors.New("empty batch request")
+		}
+		return reqs, true, nil
+	}
+	var r rpcRequest
+	if err := json.Unmarshal(trimmed, &r); err != nil {
+		return nil, false, err
+	}
+	return []rpcRequest{r}, false, nil
+}
+
+func newResponse(req *http.Request, rsps []rpcResponse, batch bool) (*http.Response, error) {
+	var data []byte
+	var err error
+	if batch {
+		data, err = json.Marshal(rsps)
+	} else {
+		data, err = json.Marshal(rsps[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+func interactionKey(method string, params json.RawMessage) (string, error) {
+	normalized, err := normalize(params)
+	if err != nil {
+		return "", err
+	}
+	return method + " " + string(normalized), nil
+}
+
+// normalize re-encodes params with sorted object keys and without insignificant whitespace,
+// so that calls with equal arguments match whatever the encoding of the caller.
+func normalize(params json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(params)) == 0 {
+		return json.RawMessage("[]"), nil
+	}
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return json.RawMessage("[]"), nil
+	}
+	// keep type tags like `0x2::coin::Coin<0x2::sui::SUI>` readable in the fixture files
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
@@ -0,0 +1,121 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+func TestCassette(t *testing.T) {
+	var checkpoint int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []models.JsonRPCMessage
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Error(err.Error())
+			return
+		}
+		var rsps []map[string]interface{}
+		for _, req := range reqs {
+			rsps = append(rsps, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"result":  []interface{}{req.Method, atomic.AddInt64(&checkpoint, 1)},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(rsps)
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	c, err := Load(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	conn := httpconn.DialWithClient(srv.URL, c.Client())
+
+	var first, second, third []interface{}
+	params := []interface{}{map[string]interface{}{"showType": true, "showContent": true}}
+	batch := []httpconn.BatchElem{
+		{Method: "sui_getObject", Params: params, Result: &first},
+		{Method: "sui_getLatestCheckpointSequenceNumber", Params: []interface{}{}, Result: &second},
+	}
+	if err := conn.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatal(err.Error())
+	}
+	batch = batch[1:]
+	batch[0].Result = &third
+	if err := conn.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err.Error())
+	}
+	srv.Close()
+
+	c, err = Load(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := len(c.Interactions()); n != 3 {
+		t.Fatalf("expected 3 recorded calls, got %d", n)
+	}
+	conn = httpconn.DialWithClient(srv.URL, c.Client())
+
+	// the params are encoded with another key order, and the repeated call replays its recordings in order
+	var object []interface{}
+	if err := conn.BatchCallContext(context.Background(), []httpconn.BatchElem{{
+		Method: "sui_getObject",
+		Params: []interface{}{json.RawMessage(`{"showContent":true, "showType":true}`)},
+		Result: &object,
+	}}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if object[0] != "sui_getObject" || object[1] != first[1] {
+		t.Errorf("unexpected replayed object %v", object)
+	}
+	for _, expected := range []interface{}{second[1], third[1], third[1]} {
+		var latest []interface{}
+		if err := conn.BatchCallContext(context.Background(), []httpconn.BatchElem{{
+			Method: "sui_getLatestCheckpointSequenceNumber",
+			Params: []interface{}{},
+			Result: &latest,
+		}}); err != nil {
+			t.Fatal(err.Error())
+		}
+		if latest[1] != expected {
+			t.Errorf("expected checkpoint %v, got %v", expected, latest[1])
+		}
+	}
+
+	var missing []interface{}
+	err = conn.CallContext(context.Background(), &missing, httpconn.Operation{Method: "sui_getEvents", Params: []interface{}{}})
+	if !errors.Is(err, ErrInteractionNotFound) {
+		t.Errorf("expected ErrInteractionNotFound, got %v", err)
+	}
+
+	// the error names the call missing from a batch, not the first call of the batch
+	err = conn.BatchCallContext(context.Background(), []httpconn.BatchElem{
+		{Method: "sui_getLatestCheckpointSequenceNumber", Params: []interface{}{}},
+		{Method: "sui_getEvents", Params: []interface{}{"0x1"}},
+	})
+	if !errors.Is(err, ErrInteractionNotFound) || !strings.Contains(err.Error(), `sui_getEvents ["0x1"]`) {
+		t.Errorf("expected the missing call to be reported, got %v", err)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	for value, expected := range map[string]Mode{"": ModeReplay, "replay": ModeReplay, "RECORD": ModeRecord, "auto": ModeReplayOrRecord, "other": ModeReplay} {
+		t.Setenv(ModeEnv, value)
+		if mode := ModeFromEnv(); mode != expected {
+			t.Errorf("%q: expected mode %d, got %d", value, expected, mode)
+		}
+	}
+}
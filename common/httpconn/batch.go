@@ -0,0 +1,127 @@
+package httpconn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+var (
+	ErrMissingBatchResponse = errors.New("response batch did not contain a response to this call")
+)
+
+// BatchElem is an element in a batch request.
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	// The result is unmarshaled into this field. Result must be set to a
+	// non-nil pointer value of the desired type, otherwise the response will be
+	// discarded.
+	Result interface{}
+	// Error is set if the server returns an error for this request, or if
+	// unmarshaling into Result fails. It is not set for I/O errors.
+	Error error
+}
+
+// BatchCallContext sends all given requests as a single batch and waits for the server
+// to return a response for all of them. The wait duration is bounded by the
+// context's deadline.
+//
+// In contrast to CallContext, BatchCallContext only returns errors that have occurred
+// while sending the request. Any error specific to a request is reported through the
+// Error field of the corresponding BatchElem.
+//
+// Note that batch calls may not be executed atomically on the server side.
+// The whole batch passes through the interceptors once, as a call of interceptor.BatchMethod.
+func (h *HttpConn) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	if len(b) == 0 {
+		return nil
+	}
+	methods := make([]interface{}, len(b))
+	for i, elem := range b {
+		methods[i] = elem.Method
+	}
+	call := &interceptor.Call{
+		Transport: interceptor.TransportHTTP,
+		Method:    interceptor.BatchMethod,
+		Params:    methods,
+		Result:    b,
+	}
+	return interceptor.Invoke(ctx, h.interceptor, call, func(ctx context.Context, call *interceptor.Call) error {
+		return h.batchCall(ctx, b)
+	})
+}
+
+func (h *HttpConn) batchCall(ctx context.Context, b []BatchElem) error {
+	msgs := make([]*models.JsonRPCMessage, len(b))
+	methods := make([]string, len(b))
+	byID := make(map[string]int, len(b))
+	for i, elem := range b {
+		msg, err := h.newMessage(elem.Method, elem.Params...)
+		if err != nil {
+			return err
+		}
+		msgs[i] = msg
+		methods[i] = elem.Method
+		byID[string(msg.ID)] = i
+		b[i].Error = nil
+	}
+
+	respBody, err := h.send(ctx, msgs, methods...)
+	if err != nil {
+		return err
+	}
+	defer respBody.Close()
+
+	body, err := io.ReadAll(respBody)
+	if err != nil {
+		return err
+	}
+
+	// Some nodes answer a batch they cannot handle with a single error object.
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var respMsg models.JsonRPCMessage
+		if err := json.Unmarshal(trimmed, &respMsg); err != nil {
+			return err
+		}
+		if respMsg.Error != nil {
+			respMsg.Error.Method = interceptor.BatchMethod
+			respMsg.Error.RequestID = respMsg.ID
+			return respMsg.Error
+		}
+		return ErrNoResult
+	}
+
+	var respMsgs []*models.JsonRPCMessage
+	if err := json.Unmarshal(body, &respMsgs); err != nil {
+		return err
+	}
+	for _, respMsg := range respMsgs {
+		i, ok := byID[string(respMsg.ID)]
+		if !ok {
+			continue
+		}
+		delete(byID, string(respMsg.ID))
+
+		elem := &b[i]
+		switch {
+		case respMsg.Error != nil:
+			respMsg.Error.Method = elem.Method
+			respMsg.Error.RequestID = respMsg.ID
+			elem.Error = respMsg.Error
+		case len(respMsg.Result) == 0:
+			elem.Error = ErrNoResult
+		case elem.Result != nil:
+			elem.Error = decodeResult(respMsg.Result, elem.Result)
+		}
+	}
+	for _, i := range byID {
+		b[i].Error = ErrMissingBatchResponse
+	}
+	return nil
+}
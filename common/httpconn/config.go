@@ -0,0 +1,140 @@
+package httpconn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+)
+
+var (
+	ErrResponseTooLarge = errors.New("JSON-RPC response exceeds the configured size limit")
+)
+
+// RateLimiter throttles the requests sent by a HttpConn.
+type RateLimiter interface {
+	// Acquire blocks until a request of the given methods may be sent to the endpoint, or fails once ctx is done.
+	// The returned release function must be called exactly once with the outcome of the request.
+	Acquire(ctx context.Context, endpoint string, methods ...string) (release func(err error), err error)
+}
+
+// Config configures a HttpConn created by DialWithConfig.
+type Config struct {
+	// Client is the HTTP client sending requests. If set, Timeout and the connection pool settings are ignored.
+	Client *http.Client
+	// Timeout bounds a single HTTP request, including reading the response.
+	Timeout time.Duration
+	// MaxIdleConns, MaxIdleConnsPerHost, MaxConnsPerHost and IdleConnTimeout size the connection pool of the transport.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	// Header is added to every request.
+	Header http.Header
+	// UserAgent is sent as the `User-Agent` header if not empty.
+	UserAgent string
+	// RetryPolicy retries transient failures, nil disables retries.
+	RetryPolicy *RetryPolicy
+	// RateLimiter throttles requests if set.
+	RateLimiter RateLimiter
+	// Interceptors wrap every call, the first interceptor is the outermost.
+	Interceptors []interceptor.Interceptor
+	// MaxResponseSize limits the size of a response body in bytes, 0 means no limit.
+	MaxResponseSize int64
+	// Logger receives the diagnostics of the connection, e.g. the retried failures, slog.Default() is used if nil.
+	Logger *slog.Logger
+}
+
+// DefaultConfig returns the configuration used by Dial.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:         30 * time.Second,
+		MaxIdleConns:    3,
+		IdleConnTimeout: 30 * time.Second,
+		RetryPolicy:     DefaultRetryPolicy(),
+	}
+}
+
+// DialWithConfig creates a connection to rpcUrl configured by cfg.
+func DialWithConfig(rpcUrl string, cfg Config) *HttpConn {
+	c := cfg.Client
+	if c == nil {
+		c = &http.Client{
+			Transport: &http.Transport{
+				MaxIdleConns:        cfg.MaxIdleConns,
+				MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+				MaxConnsPerHost:     cfg.MaxConnsPerHost,
+				IdleConnTimeout:     cfg.IdleConnTimeout,
+			},
+			Timeout: cfg.Timeout,
+		}
+	}
+
+	conn := DialWithClient(rpcUrl, c)
+	conn.SetRetryPolicy(cfg.RetryPolicy)
+	conn.header = cfg.Header.Clone()
+	conn.userAgent = cfg.UserAgent
+	conn.limiter = cfg.RateLimiter
+	conn.maxResponseSize = cfg.MaxResponseSize
+	conn.logger = cfg.Logger
+	conn.Use(cfg.Interceptors...)
+	return conn
+}
+
+// DialPoolWithConfig creates a connection configured by cfg that routes every request to the best endpoint of the pool.
+func DialPoolWithConfig(pool *Pool, cfg Config) *HttpConn {
+	if cfg.Client == nil && pool.cfg.Client != nil {
+		cfg.Client = pool.cfg.Client
+	}
+	if cfg.Header == nil {
+		cfg.Header = pool.cfg.Header
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = pool.cfg.UserAgent
+	}
+	conn := DialWithConfig(pool.endpoints[0].url, cfg)
+	conn.pool = pool
+	return conn
+}
+
+// limitedBody fails with ErrResponseTooLarge instead of silently truncating a response larger than its limit.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// probe for one more byte to tell a body of exactly the limit from a larger one
+		var probe [1]byte
+		if n, _ := io.ReadFull(b.ReadCloser, probe[:]); n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// releasingBody releases the rate limiter slot of a request once its response is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func(err error)
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	if b.release != nil {
+		b.release(nil)
+		b.release = nil
+	}
+	return err
+}
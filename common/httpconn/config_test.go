@@ -0,0 +1,137 @@
+package httpconn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+// echoServer answers every call with a string result of n bytes, and fails the first failures requests with 503.
+func echoServer(t *testing.T, n int, failures int) (*httptest.Server, *[]http.Header) {
+	var mu sync.Mutex
+	var headers []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Clone())
+		fail := len(headers) <= failures
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req models.JsonRPCMessage
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": strings.Repeat("a", n)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &headers
+}
+
+func TestDialWithConfig(t *testing.T) {
+	srv, headers := echoServer(t, 1, 1)
+	var logs bytes.Buffer
+	var intercepted []string
+	cfg := DefaultConfig()
+	cfg.Timeout = 5 * time.Second
+	cfg.Header = http.Header{"X-Api-Key": {"key"}}
+	cfg.UserAgent = "sui-go-sdk-test"
+	cfg.RetryPolicy = &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	cfg.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	cfg.Interceptors = []interceptor.Interceptor{func(ctx context.Context, call *interceptor.Call, next interceptor.Invoker) error {
+		intercepted = append(intercepted, call.Method)
+		return next(ctx, call)
+	}}
+	conn := DialWithConfig(srv.URL, cfg)
+	cfg.Header.Set("X-Api-Key", "changed")
+
+	var rsp string
+	if err := conn.CallContext(context.Background(), &rsp, Operation{Method: "sui_getChainIdentifier"}); err != nil {
+		t.Fatal(err)
+	}
+	if conn.client.Timeout != 5*time.Second || conn.RetryPolicy() != cfg.RetryPolicy {
+		t.Errorf("expected the timeout and the retry policy of the config, got %v and %+v", conn.client.Timeout, conn.RetryPolicy())
+	}
+	if len(*headers) != 2 {
+		t.Fatalf("expected the failed request to be retried, got %d requests", len(*headers))
+	}
+	for _, header := range *headers {
+		if header.Get("X-Api-Key") != "key" || header.Get("User-Agent") != "sui-go-sdk-test" {
+			t.Errorf("unexpected request headers %v", header)
+		}
+	}
+	if len(intercepted) != 1 || intercepted[0] != "sui_getChainIdentifier" {
+		t.Errorf("expected the call to pass through the interceptor once, got %v", intercepted)
+	}
+	if !strings.Contains(logs.String(), "retrying") || !strings.Contains(logs.String(), "attempt=1") {
+		t.Errorf("expected the retry to be logged, got %q", logs.String())
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	// a response holds the result and its envelope
+	const envelope = len(`{"id":1,"jsonrpc":"2.0","result":""}` + "\n")
+	srv, _ := echoServer(t, 100, 0)
+	cfg := DefaultConfig()
+	cfg.MaxResponseSize = int64(envelope + 100)
+	var rsp string
+	if err := DialWithConfig(srv.URL, cfg).CallContext(context.Background(), &rsp, Operation{Method: "sui_getChainIdentifier"}); err != nil || len(rsp) != 100 {
+		t.Errorf("expected a response of exactly the limit to be read, got %d bytes, %v", len(rsp), err)
+	}
+	cfg.MaxResponseSize -= 10
+	if err := DialWithConfig(srv.URL, cfg).CallContext(context.Background(), &rsp, Operation{Method: "sui_getChainIdentifier"}); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	for limit, expected := range map[int64]error{10: nil, 9: ErrResponseTooLarge} {
+		body := &limitedBody{ReadCloser: io.NopCloser(strings.NewReader("0123456789")), remaining: limit}
+		if _, err := io.ReadAll(body); !errors.Is(err, expected) {
+			t.Errorf("limit %d: expected %v, got %v", limit, expected, err)
+		}
+	}
+}
+
+type recordingLimiter struct {
+	acquired int
+	released []error
+}
+
+func (l *recordingLimiter) Acquire(ctx context.Context, endpoint string, methods ...string) (func(err error), error) {
+	l.acquired++
+	return func(err error) {
+		l.released = append(l.released, err)
+	}, nil
+}
+
+func TestRateLimiterRelease(t *testing.T) {
+	srv, _ := echoServer(t, 1, 1)
+	limiter := &recordingLimiter{}
+	cfg := DefaultConfig()
+	cfg.RetryPolicy = NoRetry()
+	cfg.RateLimiter = limiter
+	conn := DialWithConfig(srv.URL, cfg)
+
+	var rsp string
+	var httpErr HTTPError
+	if err := conn.CallContext(context.Background(), &rsp, Operation{Method: "sui_getChainIdentifier"}); !errors.As(err, &httpErr) {
+		t.Fatalf("expected an HTTP error, got %v", err)
+	}
+	if err := conn.CallContext(context.Background(), &rsp, Operation{Method: "sui_getChainIdentifier"}); err != nil {
+		t.Fatal(err)
+	}
+	// the slot of the failed request is released with its error, the other once its response is closed
+	if limiter.acquired != 2 || len(limiter.released) != 2 || !errors.As(limiter.released[0], &httpErr) || limiter.released[1] != nil {
+		t.Errorf("expected each slot to be released once, got %d acquired and %v released", limiter.acquired, limiter.released)
+	}
+}
@@ -0,0 +1,232 @@
+package httpconn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+var (
+	ErrNotArray = errors.New("JSON-RPC result is not an array")
+)
+
+// response is the envelope of a JSON-RPC response, its result is decoded straight into the caller's target
+// while the envelope itself is parsed.
+type response struct {
+	ID     json.RawMessage      `json:"id"`
+	Error  *models.JsonRPCError `json:"error"`
+	Result resultSink           `json:"result"`
+}
+
+type resultSink struct {
+	target interface{}
+	set    bool
+}
+
+func (r *resultSink) UnmarshalJSON(data []byte) error {
+	r.set = true
+	return decodeResult(data, r.target)
+}
+
+// decodeResult decodes a JSON-RPC result into target. Sui encodes u64 values as JSON strings,
+// a string result is decoded as the number it holds if target is numeric.
+func decodeResult(data []byte, target interface{}) error {
+	if target == nil {
+		return nil
+	}
+	err := json.Unmarshal(data, target)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Value == "string" && typeErr.Field == "" {
+		var s string
+		if json.Unmarshal(data, &s) == nil {
+			return json.Unmarshal([]byte(s), target)
+		}
+	}
+	return err
+}
+
+// DecodeResponse decodes a JSON-RPC response read from body into result in a single pass, on every transport.
+// The error of the response is returned as a *models.JsonRPCError of method, ErrNoResult if it holds no result.
+func DecodeResponse(body io.Reader, method string, result interface{}) error {
+	rsp := response{Result: resultSink{target: result}}
+	if err := json.NewDecoder(body).Decode(&rsp); err != nil {
+		return err
+	}
+	if rsp.Error != nil {
+		rsp.Error.Method = method
+		rsp.Error.RequestID = rsp.ID
+		return rsp.Error
+	}
+	if !rsp.Result.set {
+		return ErrNoResult
+	}
+	return nil
+}
+
+// StreamArray performs a JSON-RPC call and decodes the elements of an array in its result one at a time,
+// without holding the whole response in memory. field names the array in the result object, e.g. `data` for
+// paginated responses, or is empty if the result is the array itself. The other fields of the result object
+// are decoded into rest if it is not nil. Returning an error from fn stops the call with that error.
+func StreamArray[T any](ctx context.Context, h *HttpConn, op Operation, field string, rest interface{}, fn func(T) error) error {
+	call := &interceptor.Call{
+		Transport: interceptor.TransportHTTP,
+		Method:    op.Method,
+		Params:    op.Params,
+		Result:    rest,
+	}
+	return interceptor.Invoke(ctx, h.interceptor, call, func(ctx context.Context, call *interceptor.Call) error {
+		msg, err := h.newMessage(call.Method, call.Params...)
+		if err != nil {
+			return err
+		}
+		respBody, err := h.send(ctx, msg, call.Method)
+		if err != nil {
+			return err
+		}
+		defer respBody.Close()
+
+		s := &arrayStream[T]{dec: json.NewDecoder(respBody), method: call.Method, field: field, rest: call.Result, fn: fn}
+		return s.run()
+	})
+}
+
+type arrayStream[T any] struct {
+	dec    *json.Decoder
+	method string
+	field  string
+	rest   interface{}
+	fn     func(T) error
+}
+
+func (s *arrayStream[T]) run() error {
+	if err := s.expectDelim('{'); err != nil {
+		return err
+	}
+	var id json.RawMessage
+	var hasResult bool
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "id":
+			if err := s.dec.Decode(&id); err != nil {
+				return err
+			}
+		case "error":
+			var rpcErr *models.JsonRPCError
+			if err := s.dec.Decode(&rpcErr); err != nil {
+				return err
+			}
+			if rpcErr != nil {
+				rpcErr.Method = s.method
+				rpcErr.RequestID = id
+				return rpcErr
+			}
+		case "result":
+			hasResult = true
+			if err := s.result(); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := s.dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	if !hasResult {
+		return ErrNoResult
+	}
+	return nil
+}
+
+func (s *arrayStream[T]) result() error {
+	if s.field == "" {
+		return s.array()
+	}
+	if err := s.expectDelim('{'); err != nil {
+		return err
+	}
+	rest := make(map[string]json.RawMessage)
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+		if key == s.field {
+			if err := s.array(); err != nil {
+				return err
+			}
+			continue
+		}
+		var value json.RawMessage
+		if err := s.dec.Decode(&value); err != nil {
+			return err
+		}
+		rest[key] = value
+	}
+	if err := s.expectDelim('}'); err != nil {
+		return err
+	}
+	if s.rest == nil {
+		return nil
+	}
+	data, err := json.Marshal(rest)
+	if err != nil {
+		return err
+	}
+	return decodeResult(data, s.rest)
+}
+
+func (s *arrayStream[T]) array() error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%w: %v", ErrNotArray, tok)
+	}
+	for s.dec.More() {
+		var elem T
+		if err := s.dec.Decode(&elem); err != nil {
+			return err
+		}
+		if err := s.fn(elem); err != nil {
+			return err
+		}
+	}
+	return s.expectDelim(']')
+}
+
+func (s *arrayStream[T]) key() (string, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid JSON-RPC response, unexpected %v", tok)
+	}
+	return key, nil
+}
+
+func (s *arrayStream[T]) expectDelim(expected json.Delim) error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("invalid JSON-RPC response, expected %v got %v", expected, tok)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package httpconn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/gjson"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	var chainID string
+	if err := DecodeResponse(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"4c78adac"}`), "sui_getChainIdentifier", &chainID); err != nil || chainID != "4c78adac" {
+		t.Errorf("unexpected string result %q, %v", chainID, err)
+	}
+
+	var checkpoint uint64
+	if err := DecodeResponse(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"25000000"}`), "sui_getLatestCheckpointSequenceNumber", &checkpoint); err != nil || checkpoint != 25000000 {
+		t.Errorf("unexpected u64 result %d, %v", checkpoint, err)
+	}
+
+	var page models.PaginatedCheckpointsResponse
+	err := DecodeResponse(strings.NewReader(`{"jsonrpc":"2.0","id":7,"error":{"code":-32602,"message":"invalid cursor"}}`), "sui_getCheckpoints", &page)
+	var rpcErr *models.JsonRPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Method != "sui_getCheckpoints" || string(rpcErr.RequestID) != "7" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := DecodeResponse(strings.NewReader(`{"jsonrpc":"2.0","id":1}`), "sui_getCheckpoints", &page); !errors.Is(err, ErrNoResult) {
+		t.Errorf("expected ErrNoResult, got %v", err)
+	}
+}
+
+func TestStreamArray(t *testing.T) {
+	body := checkpointsResponse(3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+	conn := Dial(srv.URL)
+
+	var seqs []string
+	var rest models.PaginatedCheckpointsResponse
+	err := StreamArray(context.Background(), conn, Operation{Method: "sui_getCheckpoints", Params: []interface{}{}}, "data", &rest,
+		func(checkpoint models.CheckpointResponse) error {
+			seqs = append(seqs, checkpoint.SequenceNumber)
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if strings.Join(seqs, ",") != "0,1,2" || rest.NextCursor != "2" || !rest.HasNextPage || rest.Data != nil {
+		t.Errorf("unexpected stream %v, %+v", seqs, rest)
+	}
+
+	stop := errors.New("stop")
+	err = StreamArray(context.Background(), conn, Operation{Method: "sui_getCheckpoints", Params: []interface{}{}}, "data", nil,
+		func(models.CheckpointResponse) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+}
+
+const benchmarkPageSize = 50
+
+func objectsResponse(n int) []byte {
+	objects := make([]string, n)
+	for i := range objects {
+		objects[i] = fmt.Sprintf(`{"data":{"objectId":"0x%064x","version":"41","digest":"8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd",
+			"type":"0x2::coin::Coin<0x2::sui::SUI>","owner":{"AddressOwner":"0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e"},
+			"previousTransaction":"5G8NtDc6Ed7Ky9mPUKLdMEbVqt6qJVhYyH6L4n7oR2rK","storageRebate":"988000",
+			"content":{"dataType":"moveObject","type":"0x2::coin::Coin<0x2::sui::SUI>","hasPublicTransfer":true,
+				"fields":{"balance":"1000000000","id":{"id":"0x%064x"}}}}}`, i, i)
+	}
+	return []byte(`{"jsonrpc":"2.0","id":1,"result":[` + strings.Join(objects, ",") + `]}`)
+}
+
+func transactionsResponse(n int) []byte {
+	txs := make([]string, n)
+	for i := range txs {
+		txs[i] = fmt.Sprintf(`{"digest":"tx%d","transaction":{"data":{"messageVersion":"v1",
+			"transaction":{"kind":"ProgrammableTransaction","inputs":[{"type":"pure","valueType":"u64","value":"1000"}],
+				"transactions":[{"SplitCoins":["GasCoin",[{"Input":0}]]},{"TransferObjects":[[{"Result":0}],{"Input":1}]}]},
+			"sender":"0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e",
+			"gasData":{"payment":[{"objectId":"0x3b6a9b3e5a0d5d7f6f0cc2f2d3a2b7b5b8bff3f4e6a01e2f5c8a9d1c2b3a4f5e","version":41,"digest":"8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd"}],
+				"owner":"0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e","price":"750","budget":"10000000"}},
+			"txSignatures":["AIYbCXAhPmILpWq6xsEY/Nu310Kednlb60Qcd/nD+u2WUXLJCmoBSBkSq/RGrTCzAcvYeCqdzBGn5S9CqGn9jA8="]},
+		"effects":{"messageVersion":"v1","status":{"status":"success"},"executedEpoch":"420",
+			"gasUsed":{"computationCost":"750000","storageCost":"1976000","storageRebate":"978120","nonRefundableStorageFee":"9880"},
+			"modifiedAtVersions":[{"objectId":"0x3b6a9b3e5a0d5d7f6f0cc2f2d3a2b7b5b8bff3f4e6a01e2f5c8a9d1c2b3a4f5e","sequenceNumber":"41"}],
+			"transactionDigest":"tx%d",
+			"created":[{"owner":{"AddressOwner":"0x5"},"reference":{"objectId":"0x%064x","version":42,"digest":"8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd"}}],
+			"mutated":[{"owner":{"AddressOwner":"0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e"},"reference":{"objectId":"0x3b6a9b3e5a0d5d7f6f0cc2f2d3a2b7b5b8bff3f4e6a01e2f5c8a9d1c2b3a4f5e","version":42,"digest":"8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd"}}],
+			"gasObject":{"owner":{"AddressOwner":"0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e"},"reference":{"objectId":"0x3b6a9b3e5a0d5d7f6f0cc2f2d3a2b7b5b8bff3f4e6a01e2f5c8a9d1c2b3a4f5e","version":42,"digest":"8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd"}},
+			"dependencies":["5G8NtDc6Ed7Ky9mPUKLdMEbVqt6qJVhYyH6L4n7oR2rK"]},
+		"events":[],
+		"objectChanges":[{"type":"created","sender":"0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e","owner":{"AddressOwner":"0x5"},
+			"objectType":"0x2::coin::Coin<0x2::sui::SUI>","objectId":"0x%064x","version":"42","digest":"8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd"}],
+		"balanceChanges":[{"owner":{"AddressOwner":"0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e"},"coinType":"0x2::sui::SUI","amount":"-1748880"},
+			{"owner":{"AddressOwner":"0x5"},"coinType":"0x2::sui::SUI","amount":"1000"}],
+		"timestampMs":"1700000000000","checkpoint":"25000000"}`, i, i, i, i)
+	}
+	return []byte(`{"jsonrpc":"2.0","id":1,"result":{"data":[` + strings.Join(txs, ",") + `],"nextCursor":"tx` +
+		fmt.Sprint(n-1) + `","hasNextPage":true}}`)
+}
+
+func checkpointsResponse(n int) []byte {
+	checkpoints := make([]string, n)
+	for i := range checkpoints {
+		digests := make([]string, 20)
+		for j := range digests {
+			digests[j] = fmt.Sprintf(`"tx%d-%d"`, i, j)
+		}
+		checkpoints[i] = fmt.Sprintf(`{"epoch":"420","sequenceNumber":"%d","digest":"4hcbCGUzE6hqfPMHXRvGoagMKbMEUdNgnyuGHwKZxEDs",
+			"networkTotalTransactions":"1500000000","previousDigest":"9FkDNxr4tx1dWoyZ4V8N2JpSBqUq9Zdg3f6i7ucmQ3nH",
+			"epochRollingGasCostSummary":{"computationCost":"750000","storageCost":"1976000","storageRebate":"978120","nonRefundableStorageFee":"9880"},
+			"timestampMs":"1700000000000","transactions":[%s],"checkpointCommitments":[],
+			"validatorSignature":"gE7CnMbB6JZyx9rY6Tc7qQ=="}`, i, strings.Join(digests, ","))
+	}
+	return []byte(`{"jsonrpc":"2.0","id":1,"result":{"data":[` + strings.Join(checkpoints, ",") + `],"nextCursor":"` +
+		fmt.Sprint(n-1) + `","hasNextPage":true}}`)
+}
+
+// legacyDecode is the decode path CallContext used before: envelope, gjson, string, and unmarshal again.
+func legacyDecode(body []byte, result interface{}) error {
+	var respMsg models.JsonRPCMessage
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&respMsg); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(gjson.ParseBytes(respMsg.Result).String()), result)
+}
+
+func benchmarkDecode[T any](b *testing.B, body []byte) {
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var rsp T
+			if err := legacyDecode(body, &rsp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("single-pass", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var rsp T
+			if err := DecodeResponse(bytes.NewReader(body), "", &rsp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func benchmarkStream[T any](b *testing.B, body []byte, field string) {
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			s := &arrayStream[T]{dec: json.NewDecoder(bytes.NewReader(body)), field: field, fn: func(T) error { return nil }}
+			if err := s.run(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeMultiGetObjects(b *testing.B) {
+	body := objectsResponse(benchmarkPageSize)
+	benchmarkDecode[[]*models.SuiObjectResponse](b, body)
+	benchmarkStream[models.SuiObjectResponse](b, body, "")
+}
+
+func BenchmarkDecodeQueryTransactionBlocks(b *testing.B) {
+	body := transactionsResponse(benchmarkPageSize)
+	benchmarkDecode[models.SuiXQueryTransactionBlocksResponse](b, body)
+	benchmarkStream[models.SuiTransactionBlockResponse](b, body, "data")
+}
+
+func BenchmarkDecodeCheckpoints(b *testing.B) {
+	body := checkpointsResponse(benchmarkPageSize)
+	benchmarkDecode[models.PaginatedCheckpointsResponse](b, body)
+	benchmarkStream[models.CheckpointResponse](b, body, "data")
+}
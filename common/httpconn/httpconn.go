@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -14,22 +15,13 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/tidwall/gjson"
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/common/sui_error"
 	"github.com/yasir7ca/sui-go-sdk/models"
 )
 
-type HTTPError struct {
-	StatusCode int
-	Status     string
-	Body       []byte
-}
-
-func (err HTTPError) Error() string {
-	if len(err.Body) == 0 {
-		return err.Status
-	}
-	return fmt.Sprintf("%v: %s", err.Status, err.Body)
-}
+// HTTPError is returned when the node answers with a non-2xx status.
+type HTTPError = sui_error.HTTPError
 
 const (
 	vsn = "2.0"
@@ -43,26 +35,57 @@ type HttpConn struct {
 	idCounter uint32
 	rpcUrl    string
 	client    *http.Client
+	retry     *RetryPolicy
+	pool      *Pool
+
+	header          http.Header
+	userAgent       string
+	limiter         RateLimiter
+	maxResponseSize int64
+	logger          *slog.Logger
+
+	interceptors []interceptor.Interceptor
+	interceptor  interceptor.Interceptor
 }
 
 func Dial(rpcUrl string) *HttpConn {
-	hc := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:    3,
-			IdleConnTimeout: 30 * time.Second,
-		},
-		Timeout: 30 * time.Second,
-	}
-	return DialWithClient(rpcUrl, hc)
+	return DialWithConfig(rpcUrl, DefaultConfig())
 }
 
 func DialWithClient(rpcUrl string, c *http.Client) *HttpConn {
 	return &HttpConn{
 		rpcUrl: strings.TrimRight(rpcUrl, "/"),
 		client: c,
+		retry:  DefaultRetryPolicy(),
 	}
 }
 
+// DialPool creates a connection that routes every request to the best endpoint of the pool.
+// Retries are sent to whichever endpoint is the best at the time of the attempt.
+func DialPool(pool *Pool) *HttpConn {
+	return DialPoolWithConfig(pool, DefaultConfig())
+}
+
+// SetRetryPolicy replaces the policy used to retry failed requests, a nil policy disables retries.
+func (h *HttpConn) SetRetryPolicy(policy *RetryPolicy) {
+	if policy == nil {
+		policy = NoRetry()
+	}
+	h.retry = policy
+}
+
+// RetryPolicy returns the policy used to retry failed requests.
+func (h *HttpConn) RetryPolicy() *RetryPolicy {
+	return h.retry
+}
+
+// Use appends interceptors to the chain every call goes through, the first interceptor is the outermost.
+// It must be called before the connection is used.
+func (h *HttpConn) Use(interceptors ...interceptor.Interceptor) {
+	h.interceptors = append(h.interceptors, interceptors...)
+	h.interceptor = interceptor.Chain(h.interceptors...)
+}
+
 // CallContext performs a JSON-RPC call with the given arguments. If the context is
 // canceled before the call has successfully returned, CallContext returns immediately.
 //
@@ -72,27 +95,27 @@ func (h *HttpConn) CallContext(ctx context.Context, result interface{}, op Opera
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
-	msg, err := h.newMessage(op.Method, op.Params...)
+	call := &interceptor.Call{
+		Transport: interceptor.TransportHTTP,
+		Method:    op.Method,
+		Params:    op.Params,
+		Result:    result,
+	}
+	return interceptor.Invoke(ctx, h.interceptor, call, h.invoke)
+}
+
+func (h *HttpConn) invoke(ctx context.Context, call *interceptor.Call) error {
+	msg, err := h.newMessage(call.Method, call.Params...)
 	if err != nil {
 		return err
 	}
-	respBody, err := h.doRequest(ctx, msg)
+	respBody, err := h.send(ctx, msg, call.Method)
 	if err != nil {
 		return err
 	}
 	defer respBody.Close()
 
-	var respMsg models.JsonRPCMessage
-	if err := json.NewDecoder(respBody).Decode(&respMsg); err != nil {
-		return err
-	}
-	if respMsg.Error != nil {
-		return respMsg.Error
-	}
-	if len(respMsg.Result) == 0 {
-		return ErrNoResult
-	}
-	return json.Unmarshal([]byte(gjson.ParseBytes(respMsg.Result).String()), &result)
+	return DecodeResponse(respBody, call.Method, call.Result)
 }
 
 func (h *HttpConn) newMessage(method string, paramsIn ...interface{}) (*models.JsonRPCMessage, error) {
@@ -106,12 +129,71 @@ func (h *HttpConn) newMessage(method string, paramsIn ...interface{}) (*models.J
 	return msg, nil
 }
 
-func (h *HttpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadCloser, error) {
+// send posts msg and retries transient failures as allowed by the retry policy for the given methods.
+func (h *HttpConn) send(ctx context.Context, msg interface{}, methods ...string) (io.ReadCloser, error) {
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.rpcUrl, bytes.NewReader(body))
+	for attempt := 1; ; attempt++ {
+		respBody, err := h.doRequest(ctx, body, methods)
+		if err == nil || !h.retry.CanRetry(attempt, err, methods...) {
+			return respBody, err
+		}
+		h.log().Warn("sui rpc request failed, retrying", "methods", methods, "attempt", attempt, "error", err)
+		if waitErr := h.retry.Wait(ctx, attempt, err); waitErr != nil {
+			return nil, err
+		}
+	}
+}
+
+func (h *HttpConn) log() *slog.Logger {
+	if h.logger != nil {
+		return h.logger
+	}
+	return slog.Default()
+}
+
+func (h *HttpConn) doRequest(ctx context.Context, body []byte, methods []string) (io.ReadCloser, error) {
+	rpcUrl := h.rpcUrl
+	var e *endpoint
+	if h.pool != nil {
+		e = h.pool.pick()
+		rpcUrl = e.url
+	}
+
+	var release func(err error)
+	if h.limiter != nil {
+		var err error
+		if release, err = h.limiter.Acquire(ctx, rpcUrl, methods...); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	respBody, err := h.post(ctx, rpcUrl, body)
+	// a call abandoned by the caller says nothing about the endpoint's health
+	if e != nil && ctx.Err() == nil {
+		e.observe(time.Since(start), err, h.pool.cfg.FailureThreshold)
+	}
+	if err != nil {
+		if release != nil {
+			release(err)
+		}
+		return nil, err
+	}
+
+	if h.maxResponseSize > 0 {
+		respBody = &limitedBody{ReadCloser: respBody, remaining: h.maxResponseSize}
+	}
+	if release != nil {
+		respBody = &releasingBody{ReadCloser: respBody, release: release}
+	}
+	return respBody, nil
+}
+
+func (h *HttpConn) post(ctx context.Context, rpcUrl string, body []byte) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcUrl, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +201,15 @@ func (h *HttpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClose
 	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
 
 	req.Header.Set("Content-Type", "application/json")
+	for key, values := range h.header {
+		req.Header[key] = values
+	}
+	if h.userAgent != "" {
+		req.Header.Set("User-Agent", h.userAgent)
+	}
+	for key, values := range interceptor.HeaderFromContext(ctx) {
+		req.Header[key] = values
+	}
 
 	// do request
 	resp, err := h.client.Do(req)
@@ -132,10 +223,13 @@ func (h *HttpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClose
 			body = buf.Bytes()
 		}
 
+		resp.Body.Close()
+
 		return nil, HTTPError{
 			Status:     resp.Status,
 			StatusCode: resp.StatusCode,
 			Body:       body,
+			Header:     resp.Header,
 		}
 	}
 	return resp.Body, nil
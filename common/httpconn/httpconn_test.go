@@ -0,0 +1,319 @@
+package httpconn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/common/sui_error"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+func TestBatchCallContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []models.JsonRPCMessage
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Error(err.Error())
+			return
+		}
+		// answer in reverse order and leave out the last call
+		var rsps []map[string]interface{}
+		for i := len(reqs) - 2; i >= 0; i-- {
+			rsp := map[string]interface{}{"jsonrpc": "2.0", "id": reqs[i].ID}
+			switch reqs[i].Method {
+			case "sui_fail":
+				rsp["error"] = map[string]interface{}{"code": -32602, "message": "invalid params"}
+			case "suix_getReferenceGasPrice":
+				// Sui encodes u64 values as strings
+				rsp["result"] = "750"
+			default:
+				rsp["result"] = reqs[i].Method
+			}
+			rsps = append(rsps, rsp)
+		}
+		_ = json.NewEncoder(w).Encode(rsps)
+	}))
+	defer srv.Close()
+
+	conn := Dial(srv.URL)
+	var first, second string
+	var price uint64
+	batch := []BatchElem{
+		{Method: "sui_first", Result: &first},
+		{Method: "sui_fail", Params: []interface{}{"0x1"}},
+		{Method: "sui_second", Result: &second},
+		{Method: "suix_getReferenceGasPrice", Result: &price},
+		{Method: "sui_missing"},
+	}
+	if err := conn.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if batch[0].Error != nil || first != "sui_first" {
+		t.Errorf("unexpected first result %q, %v", first, batch[0].Error)
+	}
+	if batch[1].Error == nil || batch[1].Error.Error() != "invalid params" {
+		t.Errorf("expected invalid params error, got %v", batch[1].Error)
+	}
+	if batch[2].Error != nil || second != "sui_second" {
+		t.Errorf("unexpected second result %q, %v", second, batch[2].Error)
+	}
+	if batch[3].Error != nil || price != 750 {
+		t.Errorf("unexpected gas price %d, %v", price, batch[3].Error)
+	}
+	if !errors.Is(batch[4].Error, ErrMissingBatchResponse) {
+		t.Errorf("expected missing response error, got %v", batch[4].Error)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1)%3 != 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req models.JsonRPCMessage
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": 7})
+	}))
+	defer srv.Close()
+
+	conn := Dial(srv.URL)
+	conn.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	t.Run("reads are retried", func(t *testing.T) {
+		var rsp uint64
+		if err := conn.CallContext(context.Background(), &rsp, Operation{Method: "suix_getReferenceGasPrice"}); err != nil {
+			t.Fatal(err.Error())
+		}
+		if rsp != 7 || atomic.LoadInt32(&calls) != 3 {
+			t.Errorf("expected result after 3 attempts, got %d after %d", rsp, calls)
+		}
+	})
+
+	t.Run("executions are not retried", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		err := conn.CallContext(context.Background(), nil, Operation{Method: MethodExecuteTransactionBlock})
+		var httpErr HTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected 503 error, got %v", err)
+		}
+		if atomic.LoadInt32(&calls) != 1 {
+			t.Errorf("expected a single attempt, got %d", calls)
+		}
+
+		// RetryMethod doesn't override the exclusion of executions
+		atomic.StoreInt32(&calls, 0)
+		retryAll := Dial(srv.URL)
+		retryAll.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryMethod: func(string) bool { return true }})
+		_ = retryAll.CallContext(context.Background(), nil, Operation{Method: MethodExecuteTransactionBlock})
+		if atomic.LoadInt32(&calls) != 1 {
+			t.Errorf("expected a single attempt despite RetryMethod, got %d", calls)
+		}
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+		for attempt, expected := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second, 100: time.Second} {
+			if d := policy.Backoff(attempt, nil); d != expected {
+				t.Errorf("attempt %d: expected %v, got %v", attempt, expected, d)
+			}
+		}
+		for i := 0; i < 100; i++ {
+			if d := ExponentialBackoff(2, 100*time.Millisecond, 3, 0.5); d < 150*time.Millisecond || d > 300*time.Millisecond {
+				t.Fatalf("expected a jittered backoff within [150ms, 300ms], got %v", d)
+			}
+		}
+		if d := ExponentialBackoff(1000, time.Second, 2, 0); d != math.MaxInt64 {
+			t.Errorf("expected the backoff to saturate, got %v", d)
+		}
+	})
+}
+
+func TestPool(t *testing.T) {
+	newNode := func(checkpoint uint64, healthy *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.LoadInt32(healthy) == 0 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var req models.JsonRPCMessage
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": checkpoint})
+		}))
+	}
+	healthyA, healthyB, healthyC := int32(0), int32(1), int32(1)
+	nodeA, nodeB, nodeC := newNode(1000, &healthyA), newNode(1000, &healthyB), newNode(10, &healthyC)
+	defer nodeA.Close()
+	defer nodeB.Close()
+	defer nodeC.Close()
+
+	pool, err := NewPool([]string{nodeA.URL, nodeB.URL, nodeC.URL}, PoolConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Nanosecond,
+		ProbeInterval:    time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer pool.Close()
+	// two failed probes take the failing node out of rotation
+	pool.probeAll()
+	pool.probeAll()
+
+	conn := DialPool(pool)
+	for i := 0; i < 5; i++ {
+		var rsp uint64
+		if err := conn.CallContext(context.Background(), &rsp, Operation{Method: "sui_getLatestCheckpointSequenceNumber"}); err != nil {
+			t.Fatal(err.Error())
+		}
+		if rsp != 1000 {
+			t.Errorf("expected the call to be served by the up to date node, got checkpoint %d", rsp)
+		}
+	}
+
+	stats := pool.Stats()
+	if !stats[0].Open || stats[1].Open || !stats[2].Lagging {
+		t.Errorf("unexpected pool health %+v", stats)
+	}
+
+	atomic.StoreInt32(&healthyA, 1)
+	pool.probeAll()
+	if stats := pool.Stats(); stats[0].Open {
+		t.Errorf("expected the recovered node back in rotation %+v", stats[0])
+	}
+}
+
+func TestPoolHeaders(t *testing.T) {
+	// the node answers the requests carrying its API key only
+	keyed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" || r.Header.Get("User-Agent") != "indexer" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req models.JsonRPCMessage
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": 1000})
+	}))
+	defer keyed.Close()
+
+	pool, err := NewPool([]string{keyed.URL}, PoolConfig{
+		ProbeInterval: time.Hour,
+		Header:        http.Header{"X-Api-Key": {"key"}},
+		UserAgent:     "indexer",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer pool.Close()
+	pool.probeAll()
+	if stats := pool.Stats(); stats[0].Checkpoint != 1000 || stats[0].ErrorRate != 0 {
+		t.Errorf("expected the probe to carry the headers of the pool, got %+v", stats[0])
+	}
+
+	var rsp uint64
+	if err := DialPool(pool).CallContext(context.Background(), &rsp, Operation{Method: "sui_getLatestCheckpointSequenceNumber"}); err != nil || rsp != 1000 {
+		t.Errorf("expected the calls to carry the headers of the pool, got %d, %v", rsp, err)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.JsonRPCMessage
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  []string{req.Method, r.Header.Get("X-Api-Key")},
+		})
+	}))
+	defer srv.Close()
+
+	var order []string
+	trace := func(name string) interceptor.Interceptor {
+		return func(ctx context.Context, call *interceptor.Call, next interceptor.Invoker) error {
+			order = append(order, name)
+			return next(ctx, call)
+		}
+	}
+	rename := func(ctx context.Context, call *interceptor.Call, next interceptor.Invoker) error {
+		call.Method = "sui_renamed"
+		return next(ctx, call)
+	}
+	metrics := interceptor.NewCallMetrics()
+
+	conn := Dial(srv.URL)
+	conn.Use(
+		trace("outer"),
+		interceptor.Metrics(metrics),
+		interceptor.StaticHeaders(http.Header{"X-Api-Key": []string{"secret"}}),
+		trace("inner"),
+		rename,
+	)
+
+	var rsp []string
+	if err := conn.CallContext(context.Background(), &rsp, Operation{Method: "sui_original"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(rsp) != 2 || rsp[0] != "sui_renamed" || rsp[1] != "secret" {
+		t.Errorf("expected renamed call with api key header, got %v", rsp)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("unexpected interceptor order %v", order)
+	}
+	if stats := metrics.Snapshot(); len(stats) != 1 || stats[0].Method != "sui_original" || stats[0].Calls != 1 {
+		t.Errorf("unexpected metrics %+v", stats)
+	}
+}
+
+func TestJsonRPCError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.JsonRPCMessage
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Method == "sui_overloaded" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"error": map[string]interface{}{
+				"code":    -32002,
+				"message": "Transaction execution failed due to issues with transaction inputs",
+				"data":    []string{"Object 0x1 is not available for consumption, current version: 7"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	conn := Dial(srv.URL)
+	conn.SetRetryPolicy(nil)
+	var rsp []string
+	err := conn.CallContext(context.Background(), &rsp, Operation{Method: "sui_executeTransactionBlock", Params: []interface{}{}})
+
+	var rpcErr *models.JsonRPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected a JsonRPCError, got %v", err)
+	}
+	if rpcErr.Method != "sui_executeTransactionBlock" || rpcErr.Code != sui_error.CodeExecutionError || string(rpcErr.RequestID) != "1" {
+		t.Errorf("unexpected error fields %+v", rpcErr)
+	}
+	if !errors.Is(err, sui_error.ErrObjectVersion) || errors.Is(err, sui_error.ErrObjectLocked) {
+		t.Errorf("unexpected classification of %v", err)
+	}
+
+	err = conn.CallContext(context.Background(), &rsp, Operation{Method: "sui_overloaded", Params: []interface{}{}})
+	var httpErr HTTPError
+	if !errors.As(err, &httpErr) || !errors.Is(err, sui_error.ErrNodeOverloaded) {
+		t.Errorf("expected an overloaded HTTPError, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package httpconn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrEmptyPool = errors.New("endpoint pool needs at least one rpc url")
+)
+
+const (
+	// ewmaWeight is the weight of the latest sample in the latency and error rate moving averages.
+	ewmaWeight = 0.2
+	// errorRatePenalty scales the latency of an endpoint by its error rate when ranking endpoints.
+	errorRatePenalty = 10
+)
+
+// PoolConfig configures the health checks of a Pool, zero values are replaced by the defaults.
+type PoolConfig struct {
+	// FailureThreshold is the number of consecutive failures that takes an endpoint out of rotation, default 5.
+	FailureThreshold int
+	// OpenTimeout is the minimum time a failing endpoint stays out of rotation, default 30s.
+	OpenTimeout time.Duration
+	// ProbeInterval is the interval of the background health probes, default 10s.
+	ProbeInterval time.Duration
+	// ProbeTimeout bounds a single health probe, default 5s.
+	ProbeTimeout time.Duration
+	// MaxCheckpointLag is the number of checkpoints an endpoint may lag behind the highest checkpoint seen in the pool
+	// before it is treated as unhealthy, default 30.
+	MaxCheckpointLag uint64
+	// Client is the HTTP client used for calls and probes, the client of Dial is used if nil.
+	Client *http.Client
+	// Header is added to the probes, e.g. the API key of the endpoints, and to the calls of the connections whose
+	// Config sets no header.
+	Header http.Header
+	// UserAgent is sent as the `User-Agent` header of the probes, and of the calls of the connections whose Config
+	// sets none.
+	UserAgent string
+}
+
+func (c *PoolConfig) setDefaults() {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = 5
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30 * time.Second
+	}
+	if c.ProbeInterval <= 0 {
+		c.ProbeInterval = 10 * time.Second
+	}
+	if c.ProbeTimeout <= 0 {
+		c.ProbeTimeout = 5 * time.Second
+	}
+	if c.MaxCheckpointLag == 0 {
+		c.MaxCheckpointLag = 30
+	}
+}
+
+// EndpointStats is a snapshot of the health of an endpoint in a Pool.
+type EndpointStats struct {
+	Url        string
+	Latency    time.Duration
+	ErrorRate  float64
+	Open       bool
+	Lagging    bool
+	Checkpoint uint64
+}
+
+type endpoint struct {
+	url  string
+	conn *HttpConn
+
+	mu                  sync.Mutex
+	latency             time.Duration
+	errorRate           float64
+	consecutiveFailures int
+	open                bool
+	openedAt            time.Time
+	checkpoint          uint64
+	lagging             bool
+}
+
+// observe records the outcome of a call and opens the circuit breaker after too many consecutive failures.
+func (e *endpoint) observe(latency time.Duration, err error, threshold int) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	failed := isEndpointFailure(err)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(ewmaWeight*float64(latency) + (1-ewmaWeight)*float64(e.latency))
+	}
+	sample := 0.0
+	if failed {
+		sample = 1
+	}
+	e.errorRate = ewmaWeight*sample + (1-ewmaWeight)*e.errorRate
+
+	if !failed {
+		e.consecutiveFailures = 0
+		return
+	}
+	e.consecutiveFailures++
+	if !e.open && e.consecutiveFailures >= threshold {
+		e.open = true
+		e.openedAt = time.Now()
+	}
+}
+
+func (e *endpoint) score() float64 {
+	return float64(e.latency) * (1 + errorRatePenalty*e.errorRate)
+}
+
+func (e *endpoint) stats() EndpointStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return EndpointStats{
+		Url:        e.url,
+		Latency:    e.latency,
+		ErrorRate:  e.errorRate,
+		Open:       e.open,
+		Lagging:    e.lagging,
+		Checkpoint: e.checkpoint,
+	}
+}
+
+// isEndpointFailure reports whether err means the node itself is unhealthy, as opposed to the call being rejected.
+func isEndpointFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// Pool is a set of full node endpoints serving the same network. Calls are routed to the healthy endpoint
+// with the best latency and error rate, endpoints that keep failing or lag behind the pool's highest
+// checkpoint are taken out of rotation until a background probe finds them healthy again.
+type Pool struct {
+	cfg       PoolConfig
+	endpoints []*endpoint
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewPool creates a Pool over the given rpc urls and starts its background health probes.
+// The probes run until Close is called.
+func NewPool(rpcUrls []string, cfg PoolConfig) (*Pool, error) {
+	if len(rpcUrls) == 0 {
+		return nil, ErrEmptyPool
+	}
+	cfg.setDefaults()
+
+	p := &Pool{
+		cfg:  cfg,
+		done: make(chan struct{}),
+	}
+	probeCfg := DefaultConfig()
+	probeCfg.Client = cfg.Client
+	probeCfg.Header = cfg.Header
+	probeCfg.UserAgent = cfg.UserAgent
+	probeCfg.RetryPolicy = NoRetry()
+	for _, rpcUrl := range rpcUrls {
+		conn := DialWithConfig(rpcUrl, probeCfg)
+		p.endpoints = append(p.endpoints, &endpoint{
+			url:  strings.TrimRight(rpcUrl, "/"),
+			conn: conn,
+		})
+	}
+
+	go p.probeLoop()
+	return p, nil
+}
+
+// Close stops the background health probes.
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+// Stats returns a snapshot of the health of every endpoint in the pool.
+func (p *Pool) Stats() []EndpointStats {
+	stats := make([]EndpointStats, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		stats = append(stats, e.stats())
+	}
+	return stats
+}
+
+// pick returns the healthy endpoint with the lowest score. If every endpoint is unhealthy,
+// the best of them is returned rather than failing the call.
+func (p *Pool) pick() *endpoint {
+	var best, fallback *endpoint
+	var bestScore, fallbackScore float64
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		healthy := !e.open && !e.lagging
+		score := e.score()
+		e.mu.Unlock()
+
+		if healthy && (best == nil || score < bestScore) {
+			best, bestScore = e, score
+		}
+		if fallback == nil || score < fallbackScore {
+			fallback, fallbackScore = e, score
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return fallback
+}
+
+func (p *Pool) probeLoop() {
+	ticker := time.NewTicker(p.cfg.ProbeInterval)
+	defer ticker.Stop()
+
+	p.probeAll()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.probeAll()
+		}
+	}
+}
+
+// probeAll asks every endpoint for its latest checkpoint, closes the circuit of endpoints that answer
+// after their open timeout, and flags the endpoints lagging behind the highest checkpoint.
+func (p *Pool) probeAll() {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			p.probe(e)
+		}(e)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		if e.checkpoint > highest {
+			highest = e.checkpoint
+		}
+		e.mu.Unlock()
+	}
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		e.lagging = highest-e.checkpoint > p.cfg.MaxCheckpointLag
+		e.mu.Unlock()
+	}
+}
+
+func (p *Pool) probe(e *endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.ProbeTimeout)
+	defer cancel()
+
+	var checkpoint uint64
+	start := time.Now()
+	err := e.conn.CallContext(ctx, &checkpoint, Operation{
+		Method: "sui_getLatestCheckpointSequenceNumber",
+		Params: []interface{}{},
+	})
+	e.observe(time.Since(start), err, p.cfg.FailureThreshold)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.checkpoint = checkpoint
+	if e.open && time.Since(e.openedAt) >= p.cfg.OpenTimeout {
+		e.open = false
+		e.consecutiveFailures = 0
+	}
+}
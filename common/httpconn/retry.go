@@ -0,0 +1,173 @@
+package httpconn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// MethodExecuteTransactionBlock is never retried by the transport, resubmitting a transaction is
+// only safe after checking whether the first attempt reached the chain.
+const MethodExecuteTransactionBlock = "sui_executeTransactionBlock"
+
+// RetryPolicy configures how HttpConn retries a request that failed with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one, values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, including the delay requested by a `Retry-After` header.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after each attempt, 2 is used if it is lower than 1.
+	Multiplier float64
+	// Jitter is the fraction of the backoff that is randomized, in the range [0, 1].
+	Jitter float64
+	// Retryable classifies errors as transient, IsRetryableError is used if nil.
+	Retryable func(err error) bool
+	// RetryMethod reports whether calls of the JSON-RPC method may be retried by the transport, every method is
+	// retried if nil. `sui_executeTransactionBlock` is never retried by the transport, whatever RetryMethod says.
+	RetryMethod func(method string) bool
+}
+
+// DefaultRetryPolicy returns the policy used by Dial, it retries reads up to 3 times within a few seconds.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// NoRetry returns a policy that sends every request only once.
+func NoRetry() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 1}
+}
+
+// CanRetry reports whether a call of method that failed with err on the given attempt (starting at 1) may be retried.
+func (p *RetryPolicy) CanRetry(attempt int, err error, methods ...string) bool {
+	if p == nil || attempt >= p.MaxAttempts || err == nil {
+		return false
+	}
+	for _, method := range methods {
+		if !p.retryMethod(method) {
+			return false
+		}
+	}
+	return p.IsTransient(err)
+}
+
+// IsTransient classifies err with Retryable, or IsRetryableError if p or Retryable is nil.
+func (p *RetryPolicy) IsTransient(err error) bool {
+	if p != nil && p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryableError(err)
+}
+
+func (p *RetryPolicy) retryMethod(method string) bool {
+	if method == MethodExecuteTransactionBlock {
+		return false
+	}
+	return p.RetryMethod == nil || p.RetryMethod(method)
+}
+
+// Backoff returns the delay before the next attempt, attempt is the number of attempts already made.
+// A `Retry-After` header carried by err takes precedence over the exponential backoff.
+func (p *RetryPolicy) Backoff(attempt int, err error) time.Duration {
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		if d, ok := retryAfter(httpErr.Header); ok {
+			return p.capBackoff(d)
+		}
+	}
+
+	return p.capBackoff(ExponentialBackoff(attempt, p.InitialBackoff, p.Multiplier, p.Jitter))
+}
+
+// ExponentialBackoff returns initial grown by multiplier for each attempt after the first, shortened at random by
+// up to the jitter fraction. 2 is used if multiplier is lower than 1.
+func ExponentialBackoff(attempt int, initial time.Duration, multiplier, jitter float64) time.Duration {
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	d := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if jitter > 0 {
+		d -= d * math.Min(jitter, 1) * rand.Float64()
+	}
+	if d > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) capBackoff(d time.Duration) time.Duration {
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Wait blocks for the backoff of the given attempt, it returns early with the context error if ctx is done.
+func (p *RetryPolicy) Wait(ctx context.Context, attempt int, err error) error {
+	timer := time.NewTimer(p.Backoff(attempt, err))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsRetryableError reports whether err is a transient transport failure: a timeout, a reset or refused connection,
+// a truncated response, or an HTTP status of 408, 429, 502, 503 or 504.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryAfter parses a `Retry-After` header given either in seconds or as an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
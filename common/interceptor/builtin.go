@@ -0,0 +1,130 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Logging logs every call with the method it was issued with, transport, latency and error. Successful calls are logged at
+// debug level and failed calls at error level.
+func Logging(logger *slog.Logger) Interceptor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		method := call.Method
+		start := time.Now()
+		err := next(ctx, call)
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.String("transport", call.Transport),
+			slog.Duration("latency", time.Since(start)),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+			logger.LogAttrs(ctx, slog.LevelError, "sui rpc call failed", attrs...)
+		} else {
+			logger.LogAttrs(ctx, slog.LevelDebug, "sui rpc call", attrs...)
+		}
+		return err
+	}
+}
+
+// MetricsRecorder receives the outcome of every call, implementations must be safe for concurrent use.
+type MetricsRecorder interface {
+	ObserveCall(method string, latency time.Duration, err error)
+}
+
+// Metrics reports the latency and error of every call to recorder, keyed by the method the call was issued with.
+func Metrics(recorder MetricsRecorder) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		method := call.Method
+		start := time.Now()
+		err := next(ctx, call)
+		recorder.ObserveCall(method, time.Since(start), err)
+		return err
+	}
+}
+
+// MethodStats is the aggregated latency and error count of a method.
+type MethodStats struct {
+	Method       string
+	Calls        uint64
+	Errors       uint64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// AverageLatency returns the mean latency of the calls.
+func (s MethodStats) AverageLatency() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Calls)
+}
+
+// CallMetrics is an in-memory MetricsRecorder aggregating calls per method.
+type CallMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*MethodStats
+}
+
+// NewCallMetrics creates an empty CallMetrics.
+func NewCallMetrics() *CallMetrics {
+	return &CallMetrics{methods: make(map[string]*MethodStats)}
+}
+
+// ObserveCall implements MetricsRecorder.
+func (m *CallMetrics) ObserveCall(method string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, ok := m.methods[method]
+	if !ok {
+		stats = &MethodStats{Method: method}
+		m.methods[method] = stats
+	}
+	stats.Calls++
+	if err != nil {
+		stats.Errors++
+	}
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+}
+
+// Snapshot returns the statistics of every method seen so far, sorted by method.
+func (m *CallMetrics) Snapshot() []MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make([]MethodStats, 0, len(m.methods))
+	for _, stats := range m.methods {
+		snapshot = append(snapshot, *stats)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Method < snapshot[j].Method })
+	return snapshot
+}
+
+// StaticHeaders adds the same headers to every HTTP request, e.g. the API key of a BlockVision endpoint.
+// It has no effect on the websocket transport, whose headers are set on the handshake.
+func StaticHeaders(header http.Header) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		return next(WithHeader(ctx, header), call)
+	}
+}
+
+// DynamicHeaders computes the headers of every HTTP request, e.g. to refresh a short-lived token.
+// The call fails with the error returned by fn. The headers are ignored by the websocket transport.
+func DynamicHeaders(fn func(ctx context.Context, call *Call) (http.Header, error)) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		header, err := fn(ctx, call)
+		if err != nil {
+			return err
+		}
+		return next(WithHeader(ctx, header), call)
+	}
+}
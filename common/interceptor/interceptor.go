@@ -0,0 +1,84 @@
+package interceptor
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	TransportHTTP      = "http"
+	TransportWebsocket = "websocket"
+
+	// BatchMethod is the method of the call that wraps a JSON-RPC batch, its Params hold the method of every call in the batch.
+	BatchMethod = "rpc_batch"
+)
+
+// Call is a JSON-RPC call passing through an interceptor chain.
+type Call struct {
+	// Transport is either TransportHTTP or TransportWebsocket.
+	Transport string
+	Method    string
+	Params    []interface{}
+	// Result is the pointer the response is decoded into, it holds the decoded value once the invoker returns.
+	Result interface{}
+}
+
+// Invoker sends a call and decodes its response into call.Result.
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor wraps the invocation of a call. It can inspect or mutate the call and the context before
+// invoking next, and inspect the result and the error next returns. Returning without calling next
+// short-circuits the call.
+type Interceptor func(ctx context.Context, call *Call, next Invoker) error
+
+// Chain composes interceptors into one, the first interceptor is the outermost.
+func Chain(interceptors ...Interceptor) Interceptor {
+	var chain []Interceptor
+	for _, i := range interceptors {
+		if i != nil {
+			chain = append(chain, i)
+		}
+	}
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		return chainInvoker(chain, next)(ctx, call)
+	}
+}
+
+func chainInvoker(chain []Interceptor, final Invoker) Invoker {
+	if len(chain) == 0 {
+		return final
+	}
+	return func(ctx context.Context, call *Call) error {
+		return chain[0](ctx, call, chainInvoker(chain[1:], final))
+	}
+}
+
+// Invoke runs call through the interceptor i, ending with final. A nil interceptor invokes final directly.
+func Invoke(ctx context.Context, i Interceptor, call *Call, final Invoker) error {
+	if i == nil {
+		return final(ctx, call)
+	}
+	return i(ctx, call, final)
+}
+
+type headerKey struct{}
+
+// WithHeader returns a context carrying HTTP headers that the HTTP transport adds to the request.
+// Headers already in ctx are kept, values of the same key are replaced. The websocket transport ignores them, its
+// headers are sent once with the handshake.
+func WithHeader(ctx context.Context, header http.Header) context.Context {
+	merged := HeaderFromContext(ctx).Clone()
+	if merged == nil {
+		merged = http.Header{}
+	}
+	for key, values := range header {
+		merged[http.CanonicalHeaderKey(key)] = values
+	}
+	return context.WithValue(ctx, headerKey{}, merged)
+}
+
+// HeaderFromContext returns the HTTP headers added with WithHeader, or nil.
+func HeaderFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerKey{}).(http.Header)
+	return header
+}
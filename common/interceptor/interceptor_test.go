@@ -0,0 +1,128 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(ctx context.Context, call *Call, next Invoker) error {
+			order = append(order, name+" before")
+			err := next(ctx, call)
+			order = append(order, name+" after")
+			return err
+		}
+	}
+	final := func(ctx context.Context, call *Call) error {
+		order = append(order, "call "+call.Method)
+		return nil
+	}
+
+	chain := Chain(trace("outer"), nil, trace("inner"))
+	if err := Invoke(context.Background(), chain, &Call{Method: "sui_getChainIdentifier"}, final); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"outer before", "inner before", "call sui_getChainIdentifier", "inner after", "outer after"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+
+	// an interceptor can rewrite the call and short-circuit it
+	order = nil
+	errDenied := errors.New("denied")
+	deny := func(ctx context.Context, call *Call, next Invoker) error {
+		if call.Method == "unsafe_pay" {
+			return errDenied
+		}
+		call.Method = "rewritten"
+		return next(ctx, call)
+	}
+	if err := Invoke(context.Background(), Chain(deny), &Call{Method: "unsafe_pay"}, final); !errors.Is(err, errDenied) || len(order) != 0 {
+		t.Errorf("expected the call to be denied, got %v, %v", err, order)
+	}
+	if err := Invoke(context.Background(), Chain(deny), &Call{Method: "sui_getObject"}, final); err != nil || !reflect.DeepEqual(order, []string{"call rewritten"}) {
+		t.Errorf("expected the call to be rewritten, got %v, %v", err, order)
+	}
+
+	order = nil
+	if err := Invoke(context.Background(), nil, &Call{Method: "direct"}, final); err != nil || !reflect.DeepEqual(order, []string{"call direct"}) {
+		t.Errorf("expected a nil interceptor to invoke the call, got %v, %v", err, order)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	if HeaderFromContext(context.Background()) != nil {
+		t.Error("expected no header in a bare context")
+	}
+	ctx := WithHeader(context.Background(), http.Header{"X-Api-Key": {"a"}, "X-Trace": {"1"}})
+	ctx = WithHeader(ctx, http.Header{"x-api-key": {"b"}})
+	header := HeaderFromContext(ctx)
+	if header.Get("X-Api-Key") != "b" || len(header.Values("X-Api-Key")) != 1 || header.Get("X-Trace") != "1" {
+		t.Errorf("expected the headers to be merged, got %v", header)
+	}
+
+	var got http.Header
+	final := func(ctx context.Context, call *Call) error {
+		got = HeaderFromContext(ctx)
+		return nil
+	}
+	token := 0
+	dynamic := DynamicHeaders(func(ctx context.Context, call *Call) (http.Header, error) {
+		token++
+		if call.Method == "fail" {
+			return nil, errors.New("no token")
+		}
+		return http.Header{"Authorization": {"Bearer " + strings.Repeat("t", token)}}, nil
+	})
+	chain := Chain(StaticHeaders(http.Header{"X-Api-Key": {"key"}}), dynamic)
+	for _, expected := range []string{"Bearer t", "Bearer tt"} {
+		if err := Invoke(context.Background(), chain, &Call{Method: "sui_getObject"}, final); err != nil {
+			t.Fatal(err)
+		}
+		if got.Get("X-Api-Key") != "key" || got.Get("Authorization") != expected {
+			t.Errorf("unexpected headers %v", got)
+		}
+	}
+	got = nil
+	if err := Invoke(context.Background(), chain, &Call{Method: "fail"}, final); err == nil || got != nil {
+		t.Errorf("expected the error of DynamicHeaders to fail the call, got %v", err)
+	}
+}
+
+func TestLoggingAndMetrics(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	metrics := NewCallMetrics()
+	chain := Chain(Logging(logger), Metrics(metrics))
+
+	errFailed := errors.New("failed")
+	final := func(ctx context.Context, call *Call) error {
+		if call.Method == "sui_fail" {
+			return errFailed
+		}
+		return nil
+	}
+	for _, method := range []string{"sui_getObject", "sui_getObject", "sui_fail"} {
+		_ = Invoke(context.Background(), chain, &Call{Transport: TransportHTTP, Method: method}, final)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "level=DEBUG") || !strings.Contains(lines[2], "level=ERROR") ||
+		!strings.Contains(lines[2], "method=sui_fail") || !strings.Contains(lines[2], "error=failed") {
+		t.Errorf("unexpected logs %s", logs.String())
+	}
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].Method != "sui_fail" || snapshot[0].Errors != 1 ||
+		snapshot[1].Method != "sui_getObject" || snapshot[1].Calls != 2 || snapshot[1].Errors != 0 {
+		t.Errorf("unexpected metrics %+v", snapshot)
+	}
+}
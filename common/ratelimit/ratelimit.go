@@ -0,0 +1,303 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+)
+
+var (
+	// ErrRateLimited is returned when a request cannot be sent before its context deadline.
+	ErrRateLimited = errors.New("rate limit would exceed the context deadline")
+)
+
+// MethodClass groups JSON-RPC methods that share a limit.
+type MethodClass int
+
+const (
+	ClassRead MethodClass = iota
+	ClassDryRun
+	ClassExecute
+)
+
+func (c MethodClass) String() string {
+	switch c {
+	case ClassDryRun:
+		return "dry-run"
+	case ClassExecute:
+		return "execute"
+	default:
+		return "read"
+	}
+}
+
+// ClassOf returns the class of a JSON-RPC method.
+func ClassOf(method string) MethodClass {
+	switch method {
+	case "sui_executeTransactionBlock":
+		return ClassExecute
+	case "sui_dryRunTransactionBlock", "sui_devInspectTransactionBlock":
+		return ClassDryRun
+	}
+	return ClassRead
+}
+
+// classOfMethods returns the most restricted class of a request, a batch counts against the class of its heaviest call.
+func classOfMethods(methods []string) MethodClass {
+	class := ClassRead
+	for _, method := range methods {
+		if c := ClassOf(method); c > class {
+			class = c
+		}
+	}
+	return class
+}
+
+// Limit is the limit of one method class on one endpoint, zero values mean unlimited.
+type Limit struct {
+	// Rate is the sustained number of requests per second.
+	Rate float64
+	// Burst is the number of requests that can be sent at once, at least 1 when Rate is set.
+	Burst int
+	// MaxInFlight is the number of requests waiting for their response at the same time.
+	MaxInFlight int
+}
+
+// Limits are the limits of each method class.
+type Limits struct {
+	Read    Limit
+	DryRun  Limit
+	Execute Limit
+}
+
+func (l Limits) of(class MethodClass) Limit {
+	switch class {
+	case ClassDryRun:
+		return l.DryRun
+	case ClassExecute:
+		return l.Execute
+	default:
+		return l.Read
+	}
+}
+
+// Config configures a Limiter.
+type Config struct {
+	// Limits apply to every endpoint that has no entry in Endpoints.
+	Limits Limits
+	// Endpoints overrides Limits for specific rpc urls.
+	Endpoints map[string]Limits
+	// Adaptive lowers the rate of an endpoint when it answers `429 Too Many Requests`,
+	// then restores it step by step on successful requests.
+	Adaptive bool
+	// DecreaseFactor multiplies the rate on a 429, default 0.5.
+	DecreaseFactor float64
+	// IncreaseStep is the fraction of the configured rate restored by each successful request, default 0.05.
+	IncreaseStep float64
+	// MinRate is the lowest rate the adaptive limiter goes down to, default 10% of the configured rate.
+	MinRate float64
+}
+
+// Limiter is a token bucket rate limiter combined with a max-in-flight semaphore, kept per endpoint
+// and per method class. It implements httpconn.RateLimiter.
+//
+// A request blocks until it may be sent. If its context has a deadline that ends before that,
+// it fails fast with ErrRateLimited instead of waiting.
+type Limiter struct {
+	cfg Config
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+type bucketKey struct {
+	endpoint string
+	class    MethodClass
+}
+
+// New creates a Limiter.
+func New(cfg Config) *Limiter {
+	if cfg.DecreaseFactor <= 0 || cfg.DecreaseFactor >= 1 {
+		cfg.DecreaseFactor = 0.5
+	}
+	if cfg.IncreaseStep <= 0 {
+		cfg.IncreaseStep = 0.05
+	}
+	return &Limiter{
+		cfg:     cfg,
+		buckets: make(map[bucketKey]*bucket),
+	}
+}
+
+// Acquire implements httpconn.RateLimiter.
+func (l *Limiter) Acquire(ctx context.Context, endpoint string, methods ...string) (func(err error), error) {
+	b := l.bucket(strings.TrimRight(endpoint, "/"), classOfMethods(methods))
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	if err := b.acquireSlot(ctx); err != nil {
+		// the request is not sent, its token goes back to the bucket
+		b.refund()
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			b.releaseSlot()
+			if l.cfg.Adaptive {
+				b.adapt(err)
+			}
+		})
+	}, nil
+}
+
+// Rate returns the current rate of an endpoint and method class, which differs from the configured
+// rate while the adaptive limiter backs off.
+func (l *Limiter) Rate(endpoint string, class MethodClass) float64 {
+	b := l.bucket(strings.TrimRight(endpoint, "/"), class)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+func (l *Limiter) bucket(endpoint string, class MethodClass) *bucket {
+	key := bucketKey{endpoint: endpoint, class: class}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[key]; ok {
+		return b
+	}
+	limits, ok := l.cfg.Endpoints[endpoint]
+	if !ok {
+		limits = l.cfg.Limits
+	}
+	b := newBucket(limits.of(class), l.cfg)
+	l.buckets[key] = b
+	return b
+}
+
+type bucket struct {
+	mu        sync.Mutex
+	limit     Limit
+	rate      float64
+	minRate   float64
+	increase  float64
+	decrease  float64
+	tokens    float64
+	last      time.Time
+	slots     chan struct{}
+	unlimited bool
+}
+
+func newBucket(limit Limit, cfg Config) *bucket {
+	b := &bucket{
+		limit:     limit,
+		rate:      limit.Rate,
+		decrease:  cfg.DecreaseFactor,
+		increase:  cfg.IncreaseStep * limit.Rate,
+		minRate:   cfg.MinRate,
+		last:      time.Now(),
+		unlimited: limit.Rate <= 0,
+	}
+	if b.limit.Burst < 1 {
+		b.limit.Burst = 1
+	}
+	if b.minRate <= 0 || b.minRate > limit.Rate {
+		b.minRate = limit.Rate / 10
+	}
+	b.tokens = float64(b.limit.Burst)
+	if limit.MaxInFlight > 0 {
+		b.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return b
+}
+
+// wait reserves a token and sleeps until it is available.
+func (b *bucket) wait(ctx context.Context) error {
+	if b.unlimited {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		b.tokens++
+		b.mu.Unlock()
+		return ErrRateLimited
+	}
+	b.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.refund()
+		return ctx.Err()
+	}
+}
+
+// refund returns the token reserved by wait for a request that is not sent.
+func (b *bucket) refund() {
+	if b.unlimited {
+		return
+	}
+	b.mu.Lock()
+	b.tokens++
+	b.mu.Unlock()
+}
+
+func (b *bucket) acquireSlot(ctx context.Context) error {
+	if b.slots == nil {
+		return nil
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *bucket) releaseSlot() {
+	if b.slots != nil {
+		<-b.slots
+	}
+}
+
+// adapt halves the rate on `429 Too Many Requests` and restores it gradually on success.
+func (b *bucket) adapt(err error) {
+	if b.unlimited {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var httpErr httpconn.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		b.rate = math.Max(b.minRate, b.rate*b.decrease)
+		return
+	}
+	if err == nil {
+		b.rate = math.Min(b.limit.Rate, b.rate+b.increase)
+	}
+}
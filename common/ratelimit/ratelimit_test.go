@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+)
+
+func TestLimiter(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var tooMany atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		if tooMany.Load() {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":["ok"]}`))
+	}))
+	defer srv.Close()
+
+	limiter := New(Config{
+		Limits: Limits{
+			Read:    Limit{Rate: 1000, Burst: 100, MaxInFlight: 2},
+			Execute: Limit{Rate: 1, Burst: 1},
+		},
+		Adaptive: true,
+	})
+	cfg := httpconn.DefaultConfig()
+	cfg.RetryPolicy = nil
+	cfg.RateLimiter = limiter
+	conn := httpconn.DialWithConfig(srv.URL, cfg)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var rsp []string
+			if err := conn.CallContext(context.Background(), &rsp, httpconn.Operation{Method: "sui_getObject"}); err != nil {
+				t.Error(err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+
+	// the execute class allows one request per second, the second one can't make a short deadline
+	var rsp []string
+	if err := conn.CallContext(context.Background(), &rsp, httpconn.Operation{Method: "sui_executeTransactionBlock"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := conn.CallContext(ctx, &rsp, httpconn.Operation{Method: "sui_executeTransactionBlock"})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Errorf("expected the call to fail fast, took %v", time.Since(start))
+	}
+
+	tooMany.Store(true)
+	_ = conn.CallContext(context.Background(), &rsp, httpconn.Operation{Method: "sui_getObject"})
+	if rate := limiter.Rate(srv.URL, ClassRead); rate != 500 {
+		t.Errorf("expected the rate to be halved after a 429, got %v", rate)
+	}
+}
+
+func TestCancelledAcquireRefundsToken(t *testing.T) {
+	limiter := New(Config{Limits: Limits{Read: Limit{Rate: 1, Burst: 2, MaxInFlight: 1}}})
+	release, err := limiter.Acquire(context.Background(), "http://node", "sui_getObject")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second token is taken, then the call gives up waiting for a slot
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx, "http://node", "sui_getObject"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the slot wait to time out, got %v", err)
+	}
+	release(nil)
+
+	// without a refund the next token would be a second away
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	release, err = limiter.Acquire(ctx, "http://node", "sui_getObject")
+	if err != nil {
+		t.Fatalf("expected the token of the cancelled call to be refunded, got %v", err)
+	}
+	release(nil)
+}
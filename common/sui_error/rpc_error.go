@@ -0,0 +1,97 @@
+package sui_error
+
+import (
+	"errors"
+	"strings"
+)
+
+// JSON-RPC error codes returned by Sui full nodes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeServerError    = -32000
+	CodeExecutionError = -32002
+	CodeServerBusy     = -32009
+	CodeTransientError = -32050
+)
+
+// Sentinel errors of the known Sui failure modes, a JSON-RPC error matches them with errors.Is.
+var (
+	ErrParse              = errors.New("invalid JSON was received by the node")
+	ErrInvalidRequest     = errors.New("invalid JSON-RPC request")
+	ErrMethodNotFound     = errors.New("JSON-RPC method not found")
+	ErrInvalidParams      = errors.New("invalid JSON-RPC params")
+	ErrInternal           = errors.New("internal JSON-RPC error")
+	ErrTransient          = errors.New("transient node error, the call may succeed if retried")
+	ErrNodeOverloaded     = errors.New("node is overloaded")
+	ErrObjectNotFound     = errors.New("object not found")
+	ErrObjectLocked       = errors.New("object is locked by another transaction")
+	ErrObjectVersion      = errors.New("object version is not available for consumption")
+	ErrEquivocation       = errors.New("object was equivocated by conflicting transactions")
+	ErrInsufficientGas    = errors.New("insufficient gas")
+	ErrTransactionExpired = errors.New("transaction expired")
+)
+
+var codeErrors = map[int]error{
+	CodeParseError:     ErrParse,
+	CodeInvalidRequest: ErrInvalidRequest,
+	CodeMethodNotFound: ErrMethodNotFound,
+	CodeInvalidParams:  ErrInvalidParams,
+	CodeInternalError:  ErrInternal,
+	CodeServerBusy:     ErrNodeOverloaded,
+	CodeTransientError: ErrTransient,
+}
+
+// messageErrors maps fragments of the lowercased error message or data to the failure they describe.
+// Nodes report most failures with a generic code, so the message is the only way to tell them apart.
+var messageErrors = []struct {
+	fragment string
+	err      error
+}{
+	{"equivocat", ErrEquivocation},
+	{"objectlockconflict", ErrObjectLocked},
+	{"already locked", ErrObjectLocked},
+	{"locked objects", ErrObjectLocked},
+	{"objectversionunavailableforconsumption", ErrObjectVersion},
+	{"not available for consumption", ErrObjectVersion},
+	{"insufficientgas", ErrInsufficientGas},
+	{"insufficient gas", ErrInsufficientGas},
+	{"gasbalancetoolow", ErrInsufficientGas},
+	{"gasbudgettoolow", ErrInsufficientGas},
+	{"lower than the needed amount", ErrInsufficientGas},
+	{"transactionexpired", ErrTransactionExpired},
+	{"transaction expired", ErrTransactionExpired},
+	{"objectnotfound", ErrObjectNotFound},
+	{"could not find the referenced object", ErrObjectNotFound},
+	{"toomanytransactionspending", ErrNodeOverloaded},
+	{"overload", ErrNodeOverloaded},
+	{"too many requests", ErrNodeOverloaded},
+	{"server is busy", ErrNodeOverloaded},
+}
+
+// Classify returns the sentinel errors matching a JSON-RPC error code and message.
+func Classify(code int, message string) []error {
+	var errs []error
+	if err, ok := codeErrors[code]; ok {
+		errs = append(errs, err)
+	}
+	message = strings.ToLower(message)
+	for _, m := range messageErrors {
+		if strings.Contains(message, m.fragment) && !containsError(errs, m.err) {
+			errs = append(errs, m.err)
+		}
+	}
+	return errs
+}
+
+func containsError(errs []error, target error) bool {
+	for _, err := range errs {
+		if err == target {
+			return true
+		}
+	}
+	return false
+}
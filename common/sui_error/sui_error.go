@@ -1,6 +1,10 @@
 package sui_error
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 var (
 	ErrInvalidJson            = errors.New("invalid json response")
@@ -11,8 +15,23 @@ var (
 	ErrInvalidAddress         = errors.New("invalid address")
 )
 
+// HTTPError is returned when a node answers with a non-2xx status.
 type HTTPError struct {
 	StatusCode int
 	Status     string
 	Body       []byte
+	Header     http.Header
+}
+
+func (err HTTPError) Error() string {
+	if len(err.Body) == 0 {
+		return err.Status
+	}
+	return fmt.Sprintf("%v: %s", err.Status, err.Body)
+}
+
+// Is reports `429 Too Many Requests` and `503 Service Unavailable` as ErrNodeOverloaded.
+func (err HTTPError) Is(target error) bool {
+	return target == ErrNodeOverloaded &&
+		(err.StatusCode == http.StatusTooManyRequests || err.StatusCode == http.StatusServiceUnavailable)
 }
@@ -0,0 +1,160 @@
+package wsconn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrConnectionIdle = errors.New("websocket connection idle")
+)
+
+// KeepalivePolicy configures the heartbeats and deadlines detecting a dead connection, e.g. a half-open TCP
+// connection that would otherwise leave the subscriptions waiting forever.
+type KeepalivePolicy struct {
+	// PingInterval is the delay between two pings sent to the node, 0 sends no ping.
+	PingInterval time.Duration
+	// IdleTimeout is how long the connection may go without receiving anything, messages and pongs included,
+	// before it is considered dead and dialed again. 0 means no timeout.
+	IdleTimeout time.Duration
+	// WriteTimeout bounds every write to the connection, 0 means no timeout.
+	WriteTimeout time.Duration
+}
+
+// DefaultKeepalivePolicy pings every 20s and reconnects after 45s without receiving anything.
+func DefaultKeepalivePolicy() *KeepalivePolicy {
+	return &KeepalivePolicy{
+		PingInterval: 20 * time.Second,
+		IdleTimeout:  45 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+}
+
+// Health reports the health of a WsConn.
+type Health struct {
+	// State is the current state of the connection.
+	State ConnState
+	// RTT is the round-trip latency of the last ping answered by the node, 0 until one is answered.
+	RTT time.Duration
+	// SinceLastMessage is the time elapsed since anything was last received, messages and pongs included,
+	// or since the connection was made.
+	SinceLastMessage time.Duration
+	// Reconnects is the number of times the connection was lost and dialed again successfully.
+	Reconnects uint64
+}
+
+// health holds the counters reported by WsConn.Health.
+type health struct {
+	rtt         atomic.Int64
+	lastMessage atomic.Int64
+	reconnects  atomic.Uint64
+}
+
+func (h *health) touch() {
+	h.lastMessage.Store(time.Now().UnixNano())
+}
+
+// keepalive arms the read deadline of conn and the handlers receiving its control frames, any frame received
+// pushes the deadline back.
+func (w *WsConn) keepalive(conn *websocket.Conn) {
+	w.health.touch()
+	w.extendReadDeadline(conn)
+	conn.SetPongHandler(func(data string) error {
+		w.health.touch()
+		w.extendReadDeadline(conn)
+		if len(data) == 8 {
+			sent := int64(binary.BigEndian.Uint64([]byte(data)))
+			w.health.rtt.Store(time.Now().UnixNano() - sent)
+		}
+		return nil
+	})
+	conn.SetPingHandler(func(data string) error {
+		w.health.touch()
+		w.extendReadDeadline(conn)
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), w.writeDeadline())
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil
+		}
+		return err
+	})
+}
+
+func (w *WsConn) extendReadDeadline(conn *websocket.Conn) {
+	if w.keepalivePolicy().IdleTimeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(w.keepalivePolicy().IdleTimeout))
+	}
+}
+
+// writeDeadline returns the deadline of a write starting now, the zero time if writes have no timeout.
+func (w *WsConn) writeDeadline() time.Time {
+	if timeout := w.keepalivePolicy().WriteTimeout; timeout > 0 {
+		return time.Now().Add(timeout)
+	}
+	return time.Time{}
+}
+
+// keepalivePolicy returns the policy in use, an empty one if keepalive is disabled.
+func (w *WsConn) keepalivePolicy() *KeepalivePolicy {
+	if w.cfg.DisableKeepalive {
+		return &KeepalivePolicy{}
+	}
+	return w.cfg.Keepalive
+}
+
+// ping pings the node on the connection of s every PingInterval until the connection is lost. A ping that
+// can't be written closes the connection, the read loop then reports it lost.
+func (w *WsConn) ping(s *session) {
+	interval := w.keepalivePolicy().PingInterval
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			var payload [8]byte
+			binary.BigEndian.PutUint64(payload[:], uint64(time.Now().UnixNano()))
+			if err := s.conn.WriteControl(websocket.PingMessage, payload[:], w.writeDeadline()); err != nil {
+				w.logger.Warn("sui websocket ping failed", "url", w.wsUrl, "error", err)
+				_ = s.conn.Close()
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// idleError reports a read that timed out as ErrConnectionIdle.
+func (w *WsConn) idleError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %v", ErrConnectionIdle, err)
+	}
+	return err
+}
+
+// Health returns the health of the connection: its state, the latency of the last ping, the time since anything
+// was last received and the number of reconnections.
+func (w *WsConn) Health() Health {
+	h := Health{
+		State:      w.State(),
+		RTT:        time.Duration(w.health.rtt.Load()),
+		Reconnects: w.health.reconnects.Load(),
+	}
+	if last := w.health.lastMessage.Load(); last > 0 {
+		h.SinceLastMessage = time.Since(time.Unix(0, last))
+	}
+	return h
+}
@@ -0,0 +1,326 @@
+package wsconn
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrSpillFull = errors.New("subscription spill queue is full")
+)
+
+// Backpressure is what a subscription does with a notification arriving while its queue is full.
+type Backpressure int
+
+const (
+	// Block holds back the connection until the queue has room, the other subscriptions and calls wait too.
+	Block Backpressure = iota
+	// DropOldest drops the oldest notification of the queue to make room.
+	DropOldest
+	// DropNewest drops the notification arriving.
+	DropNewest
+	// Spill writes the notifications that don't fit in memory to a file, up to SpillMaxBytes of notifications
+	// waiting. The notifications arriving once the file is full are dropped.
+	Spill
+)
+
+func (b Backpressure) String() string {
+	switch b {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	case Spill:
+		return "spill"
+	default:
+		return "unknown"
+	}
+}
+
+// Default sizes of a subscription queue.
+const (
+	DefaultQueueSize     = 100
+	DefaultSpillMaxBytes = 64 << 20
+)
+
+// QueueConfig configures the queue holding the notifications of a subscription until they are received.
+type QueueConfig struct {
+	// Backpressure is what to do with a notification arriving while the queue is full, Block by default.
+	Backpressure Backpressure
+	// Size is the number of notifications held in memory, DefaultQueueSize if 0.
+	Size int
+	// SpillDir is the directory of the spill file, os.TempDir() if empty. The file is removed once the
+	// subscription ends.
+	SpillDir string
+	// SpillMaxBytes bounds the size of the spill file, and so the notifications waiting in it, DefaultSpillMaxBytes
+	// if 0.
+	SpillMaxBytes int64
+}
+
+// QueueStats are the counters of the queue of a subscription.
+type QueueStats struct {
+	// Depth is the number of notifications waiting in the queue, the spilled ones included.
+	Depth int
+	// Spilled is the number of notifications waiting in the spill file.
+	Spilled int
+	// Dropped is the number of notifications dropped because the queue was full.
+	Dropped uint64
+}
+
+// queue is the FIFO queue of the notifications of a subscription, between the goroutine reading the connection
+// and the one sending them to the channel of the subscription.
+type queue struct {
+	cfg QueueConfig
+
+	mu      sync.Mutex
+	mem     [][]byte
+	spill   *spillFile
+	closed  bool
+	dropped atomic.Uint64
+
+	// notEmpty and notFull are signaled when an item is pushed and popped
+	notEmpty chan struct{}
+	notFull  chan struct{}
+}
+
+func newQueue(cfg QueueConfig) *queue {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultQueueSize
+	}
+	if cfg.SpillMaxBytes <= 0 {
+		cfg.SpillMaxBytes = DefaultSpillMaxBytes
+	}
+	return &queue{
+		cfg:      cfg,
+		notEmpty: make(chan struct{}, 1),
+		notFull:  make(chan struct{}, 1),
+	}
+}
+
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// push appends data to the queue, applying the backpressure policy if it is full. It returns false if data
+// was dropped, an error is returned if the spill file failed. Block waits until done is closed.
+func (q *queue) push(data []byte, done <-chan struct{}) (bool, error) {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return false, nil
+		}
+		spilled := q.spill != nil && q.spill.count > 0
+		if len(q.mem) < q.cfg.Size && !spilled {
+			q.mem = append(q.mem, data)
+			q.mu.Unlock()
+			signal(q.notEmpty)
+			return true, nil
+		}
+
+		switch q.cfg.Backpressure {
+		case DropOldest:
+			q.mem = append(q.mem[1:], data)
+			q.mu.Unlock()
+			q.dropped.Add(1)
+			signal(q.notEmpty)
+			return false, nil
+		case DropNewest:
+			q.mu.Unlock()
+			q.dropped.Add(1)
+			return false, nil
+		case Spill:
+			err := q.spillLocked(data)
+			q.mu.Unlock()
+			if err != nil {
+				q.dropped.Add(1)
+				return false, err
+			}
+			signal(q.notEmpty)
+			return true, nil
+		default:
+			q.mu.Unlock()
+			select {
+			case <-q.notFull:
+			case <-done:
+				return false, nil
+			}
+		}
+	}
+}
+
+func (q *queue) spillLocked(data []byte) error {
+	if q.spill == nil {
+		q.spill = &spillFile{dir: q.cfg.SpillDir, maxBytes: q.cfg.SpillMaxBytes}
+	}
+	return q.spill.write(data)
+}
+
+// pop removes the oldest notification of the queue, waiting for one until done is closed.
+func (q *queue) pop(done <-chan struct{}) ([]byte, bool, error) {
+	for {
+		q.mu.Lock()
+		if len(q.mem) > 0 {
+			data := q.mem[0]
+			q.mem[0] = nil
+			q.mem = q.mem[1:]
+			// refill the memory from the spill file to keep the notifications in order
+			var err error
+			if q.spill != nil && q.spill.count > 0 && len(q.mem) < q.cfg.Size {
+				var next []byte
+				if next, err = q.readSpillLocked(); err == nil {
+					q.mem = append(q.mem, next)
+				}
+			}
+			q.mu.Unlock()
+			signal(q.notFull)
+			return data, true, err
+		}
+		if q.spill != nil && q.spill.count > 0 {
+			data, err := q.readSpillLocked()
+			q.mu.Unlock()
+			return data, err == nil, err
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.notEmpty:
+		case <-done:
+			return nil, false, nil
+		}
+	}
+}
+
+// readSpillLocked reads the next notification of the spill file, the spill file is discarded if it can't be read.
+func (q *queue) readSpillLocked() ([]byte, error) {
+	data, err := q.spill.read()
+	if err != nil {
+		q.dropped.Add(uint64(q.spill.count))
+		q.spill.remove()
+		q.spill = nil
+	}
+	return data, err
+}
+
+func (q *queue) stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := QueueStats{Depth: len(q.mem), Dropped: q.dropped.Load()}
+	if q.spill != nil {
+		stats.Spilled = q.spill.count
+		stats.Depth += q.spill.count
+	}
+	return stats
+}
+
+// close drops the notifications left and removes the spill file.
+func (q *queue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.mem = nil
+	if q.spill != nil {
+		q.spill.remove()
+		q.spill = nil
+	}
+}
+
+// spillFile is a FIFO queue of records in a temporary file used as a ring buffer of maxBytes, each record is
+// prefixed by its length and may wrap around the end of the file. maxBytes bounds the records pending, not the ones
+// ever written. The file is truncated whenever it is drained.
+type spillFile struct {
+	dir      string
+	maxBytes int64
+
+	f *os.File
+	// readOff and writeOff grow with each record, their position in the file is modulo maxBytes
+	readOff  int64
+	writeOff int64
+	count    int
+}
+
+func (s *spillFile) write(data []byte) error {
+	size := int64(4 + len(data))
+	if s.writeOff-s.readOff+size > s.maxBytes {
+		return ErrSpillFull
+	}
+	if s.f == nil {
+		f, err := os.CreateTemp(s.dir, "sui-subscription-*.queue")
+		if err != nil {
+			return err
+		}
+		s.f = f
+	}
+	record := make([]byte, size)
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[4:], data)
+	if err := s.writeAt(record, s.writeOff); err != nil {
+		return err
+	}
+	s.writeOff += size
+	s.count++
+	return nil
+}
+
+func (s *spillFile) read() ([]byte, error) {
+	var length [4]byte
+	if err := s.readAt(length[:], s.readOff); err != nil {
+		return nil, err
+	}
+	data := make([]byte, binary.BigEndian.Uint32(length[:]))
+	if err := s.readAt(data, s.readOff+4); err != nil {
+		return nil, err
+	}
+	s.readOff += int64(4 + len(data))
+	s.count--
+	if s.count == 0 {
+		// the next records overwrite the file from its start even if it can't be truncated
+		s.readOff, s.writeOff = 0, 0
+		_ = s.f.Truncate(0)
+	}
+	return data, nil
+}
+
+// writeAt writes p at the offset off of the ring, wrapping around the end of the file.
+func (s *spillFile) writeAt(p []byte, off int64) error {
+	pos := off % s.maxBytes
+	n := min(int64(len(p)), s.maxBytes-pos)
+	if _, err := s.f.WriteAt(p[:n], pos); err != nil {
+		return err
+	}
+	if n < int64(len(p)) {
+		_, err := s.f.WriteAt(p[n:], 0)
+		return err
+	}
+	return nil
+}
+
+// readAt reads p from the offset off of the ring, wrapping around the end of the file.
+func (s *spillFile) readAt(p []byte, off int64) error {
+	pos := off % s.maxBytes
+	n := min(int64(len(p)), s.maxBytes-pos)
+	if _, err := s.f.ReadAt(p[:n], pos); err != nil {
+		return err
+	}
+	if n < int64(len(p)) {
+		_, err := s.f.ReadAt(p[n:], 0)
+		return err
+	}
+	return nil
+}
+
+func (s *spillFile) remove() {
+	if s.f == nil {
+		return
+	}
+	_ = s.f.Close()
+	_ = os.Remove(s.f.Name())
+}
@@ -0,0 +1,118 @@
+package wsconn
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pushAll(t *testing.T, q *queue, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		_, _ = q.push([]byte(fmt.Sprint(i)), nil)
+	}
+}
+
+func popAll(t *testing.T, q *queue) []string {
+	t.Helper()
+	done := make(chan struct{})
+	close(done)
+	var got []string
+	for {
+		data, ok, err := q.pop(done)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			return got
+		}
+		got = append(got, string(data))
+	}
+}
+
+func TestQueue(t *testing.T) {
+	q := newQueue(QueueConfig{Backpressure: DropOldest, Size: 3})
+	pushAll(t, q, 5)
+	if stats := q.stats(); stats != (QueueStats{Depth: 3, Dropped: 2}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if got := fmt.Sprint(popAll(t, q)); got != "[2 3 4]" {
+		t.Errorf("drop oldest kept %s", got)
+	}
+
+	q = newQueue(QueueConfig{Backpressure: DropNewest, Size: 3})
+	pushAll(t, q, 5)
+	if got := fmt.Sprint(popAll(t, q)); got != "[0 1 2]" {
+		t.Errorf("drop newest kept %s", got)
+	}
+
+	dir := t.TempDir()
+	q = newQueue(QueueConfig{Backpressure: Spill, Size: 2, SpillDir: dir, SpillMaxBytes: 5 * 5})
+	pushAll(t, q, 8)
+	if stats := q.stats(); stats != (QueueStats{Depth: 7, Spilled: 5, Dropped: 1}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if got := fmt.Sprint(popAll(t, q)); got != "[0 1 2 3 4 5 6]" {
+		t.Errorf("spill kept %s", got)
+	}
+	// the drained spill file is reused
+	pushAll(t, q, 4)
+	if got := fmt.Sprint(popAll(t, q)); got != "[0 1 2 3]" {
+		t.Errorf("spill kept %s", got)
+	}
+	q.close()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("the spill file was not removed, %d files left", len(entries))
+	}
+
+	// block waits for room
+	q = newQueue(QueueConfig{Backpressure: Block, Size: 1})
+	pushAll(t, q, 1)
+	pushed := make(chan struct{})
+	go func() {
+		_, _ = q.push([]byte("1"), nil)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push didn't block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if data, _, _ := q.pop(nil); string(data) != "0" {
+		t.Errorf("unexpected %s", data)
+	}
+	<-pushed
+	if got := fmt.Sprint(popAll(t, q)); got != "[1]" {
+		t.Errorf("block kept %s", got)
+	}
+}
+
+func TestSpillSustainedBacklog(t *testing.T) {
+	// a subscriber staying a few notifications behind writes many times the size of the spill file
+	q := newQueue(QueueConfig{Backpressure: Spill, Size: 1, SpillDir: t.TempDir(), SpillMaxBytes: 64})
+	defer q.close()
+	done := make(chan struct{})
+	close(done)
+	next := 0
+	for i := 0; i < 1000; i++ {
+		if ok, err := q.push([]byte(fmt.Sprintf("%d-%s", i, strings.Repeat("x", i%7))), nil); !ok || err != nil {
+			t.Fatalf("notification %d dropped with %d waiting: %v", i, q.stats().Depth, err)
+		}
+		if i < 4 {
+			continue
+		}
+		data, ok, err := q.pop(done)
+		if !ok || err != nil {
+			t.Fatalf("unexpected pop %v, %v", ok, err)
+		}
+		if expected := fmt.Sprintf("%d-%s", next, strings.Repeat("x", next%7)); string(data) != expected {
+			t.Fatalf("expected %s, got %s", expected, data)
+		}
+		next++
+	}
+	if stats := q.stats(); stats.Depth != 4 || stats.Dropped != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,80 @@
+package wsconn
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+)
+
+var (
+	ErrClosed          = errors.New("websocket connection closed")
+	ErrReconnectFailed = errors.New("websocket reconnection failed")
+	ErrConnectionLost  = errors.New("websocket connection lost before the response")
+)
+
+// ConnState is the state of a WsConn.
+type ConnState int
+
+const (
+	// StateConnecting is the state of a new connection until its first dial succeeds.
+	StateConnecting ConnState = iota
+	// StateConnected means the connection is up and every subscription is active or being restored.
+	StateConnected
+	// StateReconnecting means the connection was lost and is being dialed again.
+	StateReconnecting
+	// StateClosed is the final state, reached by Close or when the reconnect policy gives up.
+	StateClosed
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// ReconnectPolicy configures how a lost connection is dialed again.
+type ReconnectPolicy struct {
+	// InitialBackoff is the delay before the second dial attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each failed attempt.
+	Multiplier float64
+	// Jitter randomly shortens each delay by up to this fraction, between 0 and 1.
+	Jitter float64
+	// MaxAttempts is the number of consecutive failed dials before the connection is closed, 0 retries forever.
+	MaxAttempts int
+}
+
+// DefaultReconnectPolicy retries forever, from 500ms up to 30s between attempts.
+func DefaultReconnectPolicy() *ReconnectPolicy {
+	return &ReconnectPolicy{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Backoff returns the delay before the next dial, attempt is the number of consecutive failed dials.
+func (p *ReconnectPolicy) Backoff(attempt int) time.Duration {
+	d := httpconn.ExponentialBackoff(attempt, p.InitialBackoff, p.Multiplier, p.Jitter)
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
+
+func (p *ReconnectPolicy) giveUp(attempt int) bool {
+	return p.MaxAttempts > 0 && attempt >= p.MaxAttempts
+}
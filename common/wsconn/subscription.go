@@ -1,9 +1,153 @@
 package wsconn
 
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
 type SubscriptionResp struct {
 	Jsonrpc string `json:"jsonrpc"`
 	Result  int64  `json:"result"`
 	Id      int64  `json:"id"`
 }
 
+// unsubscribeTimeout bounds the unsubscribe call sent by Unsubscribe.
+const unsubscribeTimeout = 10 * time.Second
+
+// Subscription is a subscription made with Subscribe.
+type Subscription struct {
+	w      *WsConn
+	method string
+	params []interface{}
+	ch     chan []byte
+
+	// id is the subscription id on the current connection, guarded by w.mu like removed
+	id      int64
+	removed bool
+
+	queue *queue
+	err   chan error
+	done  chan struct{}
+	once  sync.Once
+}
+
+// ID returns the id of the subscription on the current connection, it changes on every reconnection.
+func (s *Subscription) ID() int64 {
+	s.w.mu.Lock()
+	defer s.w.mu.Unlock()
+	return s.id
+}
+
+// QueueStats returns the counters of the queue holding the notifications of the subscription until they are received.
+func (s *Subscription) QueueStats() QueueStats {
+	return s.queue.stats()
+}
+
+// Err returns a channel receiving the error ending the subscription, e.g. ErrClosed once the connection is
+// closed. It is closed without an error when the subscription is unsubscribed or its context is done.
+func (s *Subscription) Err() <-chan error {
+	return s.err
+}
+
+// Done returns a channel closed once the subscription ended, no notification is delivered after it is closed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Unsubscribe stops the subscription and cancels it on the node with the matching unsubscribe method,
+// e.g. `suix_unsubscribeEvent`. It can be called several times, the error of the unsubscribe call is returned
+// the first time.
+func (s *Subscription) Unsubscribe() error {
+	w := s.w
+	w.mu.Lock()
+	if s.removed {
+		w.mu.Unlock()
+		return nil
+	}
+	id, session, routed := s.id, w.session, w.routes[s.id] == s
+	w.removeSubscription(s)
+	w.mu.Unlock()
+	s.end(nil)
+
+	if session == nil || !routed {
+		// the subscription is issued again on the next connection, which won't happen anymore
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(w.ctx, unsubscribeTimeout)
+	defer cancel()
+	return w.unsubscribe(ctx, session, s.method, id)
+}
+
+// Resubscribe cancels the subscription on the node and issues it again on the current connection, the handle
+// and its channel are kept. It fails with ErrClosed if the subscription ended.
+func (s *Subscription) Resubscribe(ctx context.Context) error {
+	w := s.w
+	w.mu.Lock()
+	if s.removed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+	id, session, routed := s.id, w.session, w.routes[s.id] == s
+	if routed {
+		delete(w.routes, id)
+	}
+	w.mu.Unlock()
 
+	if routed && session != nil {
+		if err := w.unsubscribe(ctx, session, s.method, id); err != nil {
+			w.logger.Warn("sui websocket unsubscribe failed", "url", w.wsUrl, "method", s.method, "subscription", id, "error", err)
+		}
+	}
+	return w.issue(ctx, s)
+}
+
+// watch unsubscribes once ctx is done.
+func (s *Subscription) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		_ = s.Unsubscribe()
+	case <-s.done:
+	}
+}
+
+// pump sends the notifications of the queue to the channel of the subscription until it ends.
+func (s *Subscription) pump() {
+	for {
+		data, ok, err := s.queue.pop(s.done)
+		if err != nil {
+			s.w.logger.Warn("sui websocket spill queue failed", "url", s.w.wsUrl, "method", s.method, "error", err)
+		}
+		if !ok {
+			select {
+			case <-s.done:
+				return
+			default:
+				continue
+			}
+		}
+		select {
+		case s.ch <- data:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Subscription) end(err error) {
+	s.once.Do(func() {
+		if err != nil {
+			s.err <- err
+		}
+		close(s.err)
+		close(s.done)
+		s.queue.close()
+	})
+}
+
+// unsubscribeMethod returns the method cancelling a subscription made with method,
+// e.g. `suix_unsubscribeEvent` for `suix_subscribeEvent`.
+func unsubscribeMethod(method string) string {
+	return strings.Replace(method, "_subscribe", "_unsubscribe", 1)
+}
@@ -1,20 +1,55 @@
 package wsconn
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
-	"github.com/tidwall/gjson"
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
 	"github.com/yasir7ca/sui-go-sdk/models"
 )
 
+// WsConn is a websocket connection to a Sui node. Calls and subscriptions are multiplexed on the connection:
+// responses are matched to their call by request id and notifications are routed to their subscription by
+// subscription id. A lost connection is dialed again according to the reconnect policy and every subscription
+// made with Call is issued again on the new connection.
 type WsConn struct {
-	Conn  *websocket.Conn
-	wsUrl string
+	// Conn is the current websocket connection, it is replaced on every reconnection and nil while disconnected.
+	Conn   *websocket.Conn
+	wsUrl  string
+	cfg    Config
+	header http.Header
+	logger *slog.Logger
+
+	interceptors []interceptor.Interceptor
+	interceptor  interceptor.Interceptor
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	nextID atomic.Int64
+	health health
+
+	mu      sync.Mutex
+	state   ConnState
+	session *session
+	ready   chan struct{}
+	// pending holds the calls waiting for their response by request id
+	pending map[int64]*pendingCall
+	// subs holds the subscriptions to issue again after a reconnection
+	subs map[*Subscription]struct{}
+	// routes holds the subscriptions of the current connection by subscription id
+	routes map[int64]*Subscription
+
+	stateMu sync.Mutex
 }
 
 type CallOp struct {
@@ -22,71 +57,654 @@ type CallOp struct {
 	Params []interface{}
 }
 
+// Config configures a WsConn created by NewWsConnWithConfig.
+type Config struct {
+	// Header is sent with the websocket handshake.
+	Header http.Header
+	// UserAgent is sent as the `User-Agent` header of the handshake if not empty.
+	UserAgent string
+	// HandshakeTimeout bounds the websocket handshake, 0 means no timeout.
+	HandshakeTimeout time.Duration
+	// ReadLimit is the maximum size of a message read from the peer in bytes, 0 means no limit.
+	ReadLimit int64
+	// Interceptors wrap every call, the first interceptor is the outermost. Headers added by interceptors are
+	// ignored, set them on the handshake with Header.
+	Interceptors []interceptor.Interceptor
+	// Logger receives the diagnostics of the connection, slog.Default() is used if nil.
+	Logger *slog.Logger
+	// Reconnect configures how a lost connection is dialed again, DefaultReconnectPolicy() is used if nil.
+	Reconnect *ReconnectPolicy
+	// DisableReconnect closes the connection instead of dialing it again once it is lost.
+	DisableReconnect bool
+	// Keepalive configures the pings and the deadlines detecting a dead connection, DefaultKeepalivePolicy() is
+	// used if nil.
+	Keepalive *KeepalivePolicy
+	// DisableKeepalive sends no ping and sets no deadline.
+	DisableKeepalive bool
+	// Queue configures the queue of the subscriptions made without their own QueueConfig.
+	Queue QueueConfig
+	// OnStateChange is called on every state change of the connection with the error that caused it, if any.
+	// It is called from the goroutine running the connection and must not block.
+	OnStateChange func(state ConnState, err error)
+}
+
+// session is one websocket connection of a WsConn, done is closed when it is lost.
+type session struct {
+	conn         *websocket.Conn
+	done         chan struct{}
+	writeTimeout time.Duration
+	writeMu      sync.Mutex
+}
+
+// write sends data on the connection, a write that fails or times out closes the connection since the frame
+// may have been partially written.
+func (s *session) write(data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.writeTimeout > 0 {
+		_ = s.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+	}
+	err := s.conn.WriteMessage(websocket.TextMessage, data)
+	if err != nil {
+		_ = s.conn.Close()
+	}
+	return err
+}
+
+// pendingCall is a call waiting for its response, the subscription of a subscribe call is routed as soon as
+// the response is read so that no notification following it is missed. A subscribe call whose caller gave up
+// is orphaned: it keeps waiting for its response to cancel the subscription the node made anyway.
+type pendingCall struct {
+	ch       chan []byte
+	sub      *Subscription
+	session  *session
+	orphaned bool
+}
+
 func NewWsConn(wsUrl string) *WsConn {
-	dialer := websocket.Dialer{}
-	conn, _, err := dialer.Dial(wsUrl, nil)
+	return NewWsConnWithConfig(wsUrl, Config{})
+}
 
+// NewWsConnWithConfig connects to wsUrl with the handshake and read settings of cfg. If the first dial fails,
+// the error is logged and the connection keeps being dialed in the background like a lost connection.
+func NewWsConnWithConfig(wsUrl string, cfg Config) *WsConn {
+	w := newWsConn(wsUrl, cfg)
+	conn, err := w.dial()
 	if err != nil {
-		log.Fatal("Error connecting to Websocket Server:", err, wsUrl)
+		w.logger.Error("sui websocket dial failed", "url", wsUrl, "error", err)
 	}
+	go w.run(conn, err)
+	return w
+}
 
-	return &WsConn{
-		Conn:  conn,
-		wsUrl: wsUrl,
+// Dial connects to wsUrl with the settings of cfg and returns the error of the first dial instead of retrying it.
+// Once connected, a lost connection is dialed again like with NewWsConnWithConfig.
+func Dial(ctx context.Context, wsUrl string, cfg Config) (*WsConn, error) {
+	w := newWsConn(wsUrl, cfg)
+	dialer := w.dialer()
+	conn, _, err := dialer.DialContext(ctx, wsUrl, w.header)
+	if err != nil {
+		w.cancel()
+		return nil, err
 	}
+	go w.run(conn, nil)
+	return w, nil
 }
 
-func (w *WsConn) Call(ctx context.Context, op CallOp, receiveMsgCh chan []byte) error {
-	jsonRPCCall := models.JsonRPCRequest{
-		JsonRPC: "2.0",
-		ID:      time.Now().UnixMilli(),
-		Method:  op.Method,
-		Params:  op.Params,
+func newWsConn(wsUrl string, cfg Config) *WsConn {
+	header := cfg.Header.Clone()
+	if cfg.UserAgent != "" {
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("User-Agent", cfg.UserAgent)
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.Reconnect == nil {
+		cfg.Reconnect = DefaultReconnectPolicy()
+	}
+	if cfg.Keepalive == nil {
+		cfg.Keepalive = DefaultKeepalivePolicy()
 	}
 
-	callBytes, err := json.Marshal(jsonRPCCall)
-	if err != nil {
-		return err
+	w := &WsConn{
+		wsUrl:   wsUrl,
+		cfg:     cfg,
+		header:  header,
+		logger:  logger,
+		state:   StateConnecting,
+		ready:   make(chan struct{}),
+		pending: make(map[int64]*pendingCall),
+		subs:    make(map[*Subscription]struct{}),
+		routes:  make(map[int64]*Subscription),
 	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.Use(cfg.Interceptors...)
+	return w
+}
 
-	err = w.Conn.WriteMessage(websocket.TextMessage, callBytes)
-	if nil != err {
-		return err
+// Use appends interceptors to the chain every call goes through, the first interceptor is the outermost.
+// Subscription notifications are not calls and bypass the chain. It must be called before the connection is used.
+func (w *WsConn) Use(interceptors ...interceptor.Interceptor) {
+	w.interceptors = append(w.interceptors, interceptors...)
+	w.interceptor = interceptor.Chain(w.interceptors...)
+}
+
+// State returns the current state of the connection.
+func (w *WsConn) State() ConnState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// Close closes the connection and stops reconnecting, pending and future calls fail with ErrClosed.
+func (w *WsConn) Close() error {
+	w.shutdown(nil)
+	return nil
+}
+
+// CallContext performs a JSON-RPC call on the connection and decodes its result into result. Calls run
+// concurrently with each other and with the subscriptions. A call in flight when the connection is lost fails
+// with ErrConnectionLost and is not sent again.
+func (w *WsConn) CallContext(ctx context.Context, result interface{}, op CallOp) error {
+	return w.CallWithRetry(ctx, result, op, nil)
+}
+
+// CallWithRetry is CallContext retrying the transient failures under policy, ErrConnectionLost included if the
+// policy classifies it as transient. The attempts run inside the interceptors, which see a single call as they do
+// over HTTP. A nil policy sends the call once.
+func (w *WsConn) CallWithRetry(ctx context.Context, result interface{}, op CallOp, policy *httpconn.RetryPolicy) error {
+	call := &interceptor.Call{
+		Transport: interceptor.TransportWebsocket,
+		Method:    op.Method,
+		Params:    op.Params,
+		Result:    result,
 	}
+	return interceptor.Invoke(ctx, w.interceptor, call, func(ctx context.Context, call *interceptor.Call) error {
+		for attempt := 1; ; attempt++ {
+			err := w.attempt(ctx, call)
+			if !policy.CanRetry(attempt, err, call.Method) {
+				return err
+			}
+			if waitErr := policy.Wait(ctx, attempt, err); waitErr != nil {
+				return err
+			}
+		}
+	})
+}
 
-	_, messageData, err := w.Conn.ReadMessage()
-	if nil != err {
+func (w *WsConn) attempt(ctx context.Context, call *interceptor.Call) error {
+	s, err := w.connection(ctx)
+	if err != nil {
 		return err
 	}
+	return w.roundTrip(ctx, s, call, nil)
+}
+
+// Subscribe subscribes with op and sends the notifications of the subscription to ch, until the subscription is
+// unsubscribed, ctx is done or the connection is closed. The subscription is issued again after every reconnection.
+// Notifications wait in a queue configured by Config.Queue until ch receives them, ch is never closed.
+func (w *WsConn) Subscribe(ctx context.Context, op CallOp, ch chan []byte) (*Subscription, error) {
+	return w.SubscribeWithQueue(ctx, op, ch, w.cfg.Queue)
+}
 
-	var rsp SubscriptionResp
-	if gjson.ParseBytes(messageData).Get("error").Exists() {
-		return fmt.Errorf(gjson.ParseBytes(messageData).Get("error").String())
+// SubscribeWithQueue is like Subscribe, with the queue of the subscription configured by queue.
+func (w *WsConn) SubscribeWithQueue(ctx context.Context, op CallOp, ch chan []byte, queue QueueConfig) (*Subscription, error) {
+	sub, err := w.newSubscription(ctx, op, ch, queue)
+	if err != nil {
+		return nil, err
 	}
+	go sub.watch(ctx)
+	return sub, nil
+}
 
-	err = json.Unmarshal([]byte(gjson.ParseBytes(messageData).String()), &rsp)
+// Call subscribes with op and sends the notifications of the subscription to receiveMsgCh until the connection
+// is closed, ctx only bounds the subscribe call.
+//
+// Deprecated: use Subscribe, which returns a handle to unsubscribe.
+func (w *WsConn) Call(ctx context.Context, op CallOp, receiveMsgCh chan []byte) error {
+	sub, err := w.newSubscription(ctx, op, receiveMsgCh, w.cfg.Queue)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("establish successfully, subscriptionID: %d, Waiting to accept data...\n", rsp.Result)
+	w.logger.Info("sui websocket subscribed", "url", w.wsUrl, "method", op.Method, "subscription", sub.ID())
+	return nil
+}
 
-	go func(conn *websocket.Conn) {
-		for {
-			messageType, messageData, err := conn.ReadMessage()
-			if nil != err {
-				log.Println(err)
-				break
+func (w *WsConn) newSubscription(ctx context.Context, op CallOp, ch chan []byte, queue QueueConfig) (*Subscription, error) {
+	sub := &Subscription{
+		w:      w,
+		method: op.Method,
+		params: op.Params,
+		ch:     ch,
+		queue:  newQueue(queue),
+		err:    make(chan error, 1),
+		done:   make(chan struct{}),
+	}
+	go sub.pump()
+	if err := w.issue(ctx, sub); err != nil {
+		w.mu.Lock()
+		w.removeSubscription(sub)
+		w.mu.Unlock()
+		sub.end(nil)
+		return nil, err
+	}
+	return sub, nil
+}
+
+// issue issues sub on the current connection, or on the next one if the connection is lost meanwhile.
+func (w *WsConn) issue(ctx context.Context, sub *Subscription) error {
+	for {
+		err := w.subscribe(ctx, nil, sub)
+		if errors.Is(err, ErrConnectionLost) && ctx.Err() == nil {
+			continue
+		}
+		return err
+	}
+}
+
+// subscribe issues sub on the connection of s, or on the current connection if s is nil.
+func (w *WsConn) subscribe(ctx context.Context, s *session, sub *Subscription) error {
+	var id int64
+	call := &interceptor.Call{
+		Transport: interceptor.TransportWebsocket,
+		Method:    sub.method,
+		Params:    sub.params,
+		Result:    &id,
+	}
+	return interceptor.Invoke(ctx, w.interceptor, call, func(ctx context.Context, call *interceptor.Call) error {
+		on := s
+		if on == nil {
+			var err error
+			if on, err = w.connection(ctx); err != nil {
+				return err
 			}
-			switch messageType {
-			case websocket.TextMessage:
-				receiveMsgCh <- messageData
+		}
+		return w.roundTrip(ctx, on, call, sub)
+	})
+}
+
+// removeSubscription stops routing the notifications of sub, w.mu must be held.
+func (w *WsConn) removeSubscription(sub *Subscription) {
+	sub.removed = true
+	delete(w.subs, sub)
+	if w.routes[sub.id] == sub {
+		delete(w.routes, sub.id)
+	}
+}
 
-			default:
+// unsubscribe cancels the subscription id made with method on the connection of s.
+func (w *WsConn) unsubscribe(ctx context.Context, s *session, method string, id int64) error {
+	var ok bool
+	call := &interceptor.Call{
+		Transport: interceptor.TransportWebsocket,
+		Method:    unsubscribeMethod(method),
+		Params:    []interface{}{id},
+		Result:    &ok,
+	}
+	err := interceptor.Invoke(ctx, w.interceptor, call, func(ctx context.Context, call *interceptor.Call) error {
+		return w.roundTrip(ctx, s, call, nil)
+	})
+	if errors.Is(err, ErrClosed) || errors.Is(err, ErrConnectionLost) {
+		// the subscription is gone with the connection
+		return nil
+	}
+	return err
+}
+
+// run owns the connection: it reads from the current connection and dials a new one once it is lost.
+func (w *WsConn) run(conn *websocket.Conn, err error) {
+	attempt := 0
+	if conn == nil {
+		attempt = 1
+	}
+	for {
+		if conn == nil {
+			if w.cfg.DisableReconnect || w.cfg.Reconnect.giveUp(attempt) {
+				w.shutdown(fmt.Errorf("%w after %d attempts: %v", ErrReconnectFailed, attempt, err))
+				return
+			}
+			if attempt > 0 {
+				select {
+				case <-time.After(w.cfg.Reconnect.Backoff(attempt)):
+				case <-w.ctx.Done():
+					return
+				}
+			}
+			conn, err = w.dial()
+			if err != nil {
+				if w.ctx.Err() != nil {
+					return
+				}
+				attempt++
+				w.logger.Warn("sui websocket reconnection failed", "url", w.wsUrl, "attempt", attempt, "error", err)
 				continue
 			}
+			if w.State() == StateReconnecting {
+				w.health.reconnects.Add(1)
+				w.logger.Info("sui websocket reconnected", "url", w.wsUrl)
+			}
 		}
-	}(w.Conn)
+		attempt = 0
 
-	return nil
+		err = w.serve(conn)
+		conn = nil
+		if w.ctx.Err() != nil {
+			return
+		}
+		w.logger.Warn("sui websocket connection lost", "url", w.wsUrl, "error", err)
+		if w.cfg.DisableReconnect {
+			w.shutdown(err)
+			return
+		}
+		w.setState(StateReconnecting, err)
+	}
+}
+
+func (w *WsConn) dialer() *websocket.Dialer {
+	return &websocket.Dialer{HandshakeTimeout: w.cfg.HandshakeTimeout}
+}
+
+func (w *WsConn) dial() (*websocket.Conn, error) {
+	conn, _, err := w.dialer().DialContext(w.ctx, w.wsUrl, w.header)
+	return conn, err
+}
+
+// serve makes conn the current connection, issues the subscriptions again and reads from conn until it is lost.
+func (w *WsConn) serve(conn *websocket.Conn) error {
+	if w.cfg.ReadLimit > 0 {
+		conn.SetReadLimit(w.cfg.ReadLimit)
+	}
+	s := &session{conn: conn, done: make(chan struct{}), writeTimeout: w.keepalivePolicy().WriteTimeout}
+	w.keepalive(conn)
+
+	w.mu.Lock()
+	if w.state == StateClosed {
+		w.mu.Unlock()
+		_ = conn.Close()
+		return ErrClosed
+	}
+	w.Conn = conn
+	w.session = s
+	close(w.ready)
+	subs := make([]*Subscription, 0, len(w.subs))
+	for sub := range w.subs {
+		subs = append(subs, sub)
+	}
+	w.mu.Unlock()
+	w.setState(StateConnected, nil)
+
+	if len(subs) > 0 {
+		go w.resubscribe(s, subs)
+	}
+	go w.ping(s)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-w.ctx.Done():
+			_ = conn.Close()
+		case <-stop:
+		}
+	}()
+
+	for {
+		messageType, messageData, err := conn.ReadMessage()
+		if err != nil {
+			w.mu.Lock()
+			w.Conn = nil
+			w.session = nil
+			w.ready = make(chan struct{})
+			w.routes = make(map[int64]*Subscription)
+			for id, p := range w.pending {
+				if p.orphaned && p.session == s {
+					// the subscriptions of the orphaned calls are gone with the connection
+					delete(w.pending, id)
+				}
+			}
+			w.mu.Unlock()
+			close(s.done)
+			_ = conn.Close()
+			return w.idleError(err)
+		}
+		w.health.touch()
+		if messageType == websocket.TextMessage {
+			w.dispatch(messageData)
+		}
+		// a subscription blocking the connection doesn't count as idle
+		w.extendReadDeadline(conn)
+	}
+}
+
+// resubscribe issues subs again on the connection of s and routes their notifications to the new subscription ids.
+func (w *WsConn) resubscribe(s *session, subs []*Subscription) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for _, sub := range subs {
+		if err := w.subscribe(ctx, s, sub); err != nil {
+			if ctx.Err() != nil {
+				// the connection was lost, the next one issues the subscriptions again
+				return
+			}
+			w.logger.Error("sui websocket resubscription failed", "url", w.wsUrl, "method", sub.method, "error", err)
+			continue
+		}
+		w.logger.Debug("sui websocket resubscribed", "url", w.wsUrl, "method", sub.method, "subscription", sub.ID())
+	}
+}
+
+type frame struct {
+	ID     *int64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type notificationParams struct {
+	Subscription int64 `json:"subscription"`
+}
+
+// dispatch routes a frame to the call waiting for it, or to the channel of its subscription.
+func (w *WsConn) dispatch(data []byte) {
+	var f frame
+	if err := json.Unmarshal(data, &f); err != nil {
+		w.logger.Warn("sui websocket received an invalid message", "url", w.wsUrl, "error", err)
+		return
+	}
+
+	if f.Method == "" {
+		if f.ID == nil {
+			return
+		}
+		w.mu.Lock()
+		p, ok := w.pending[*f.ID]
+		if ok {
+			delete(w.pending, *f.ID)
+			if p.sub != nil {
+				w.route(p, data)
+			}
+		}
+		w.mu.Unlock()
+		if ok {
+			p.ch <- data
+		}
+		return
+	}
+
+	var params notificationParams
+	if err := json.Unmarshal(f.Params, &params); err != nil {
+		w.logger.Warn("sui websocket received an invalid notification", "url", w.wsUrl, "error", err)
+		return
+	}
+	w.mu.Lock()
+	sub, ok := w.routes[params.Subscription]
+	w.mu.Unlock()
+	if !ok || sub.method != f.Method {
+		w.logger.Debug("sui websocket received a notification of an unknown subscription", "url", w.wsUrl, "method", f.Method, "subscription", params.Subscription)
+		return
+	}
+	if _, err := sub.queue.push(data, sub.done); err != nil && !errors.Is(err, ErrSpillFull) {
+		w.logger.Warn("sui websocket spill queue failed", "url", w.wsUrl, "method", sub.method, "error", err)
+	}
+}
+
+// route registers the subscription of p under the subscription id of a successful subscribe response,
+// w.mu must be held.
+func (w *WsConn) route(p *pendingCall, data []byte) {
+	var id int64
+	if err := httpconn.DecodeResponse(bytes.NewReader(data), p.sub.method, &id); err != nil {
+		return
+	}
+	sub := p.sub
+	if sub.removed || p.orphaned {
+		// unsubscribed while the subscription was being issued again, or the subscribe call was given up
+		go func() {
+			ctx, cancel := context.WithTimeout(w.ctx, unsubscribeTimeout)
+			defer cancel()
+			_ = w.unsubscribe(ctx, p.session, sub.method, id)
+		}()
+		return
+	}
+	if old := sub.id; old != id && w.routes[old] == sub {
+		// issued twice on the connection, e.g. by Resubscribe racing a reconnection
+		delete(w.routes, old)
+		go func() {
+			ctx, cancel := context.WithTimeout(w.ctx, unsubscribeTimeout)
+			defer cancel()
+			_ = w.unsubscribe(ctx, p.session, sub.method, old)
+		}()
+	}
+	sub.id = id
+	w.subs[sub] = struct{}{}
+	w.routes[id] = sub
+}
+
+func (w *WsConn) setState(state ConnState, err error) {
+	w.stateMu.Lock()
+	defer w.stateMu.Unlock()
+	w.mu.Lock()
+	if w.state == state || (w.state == StateClosed && state != StateClosed) {
+		w.mu.Unlock()
+		return
+	}
+	w.state = state
+	w.mu.Unlock()
+	if w.cfg.OnStateChange != nil {
+		w.cfg.OnStateChange(state, err)
+	}
+}
+
+// shutdown closes the connection and ends the subscriptions with err, or ErrClosed if err is nil.
+func (w *WsConn) shutdown(err error) {
+	w.cancel()
+	w.mu.Lock()
+	subs := make([]*Subscription, 0, len(w.subs))
+	for sub := range w.subs {
+		w.removeSubscription(sub)
+		subs = append(subs, sub)
+	}
+	w.mu.Unlock()
+	endErr := err
+	if endErr == nil {
+		endErr = ErrClosed
+	}
+	for _, sub := range subs {
+		sub.end(endErr)
+	}
+	w.setState(StateClosed, err)
+}
+
+// connection waits for the current connection.
+func (w *WsConn) connection(ctx context.Context) (*session, error) {
+	for {
+		w.mu.Lock()
+		s, ready, state := w.session, w.ready, w.state
+		w.mu.Unlock()
+		if state == StateClosed {
+			return nil, ErrClosed
+		}
+		if s != nil {
+			return s, nil
+		}
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-w.ctx.Done():
+			return nil, ErrClosed
+		}
+	}
+}
+
+// roundTrip sends a call on the connection of s and waits for its response, sub is routed if it is a subscribe call.
+func (w *WsConn) roundTrip(ctx context.Context, s *session, call *interceptor.Call, sub *Subscription) error {
+	id := w.nextID.Add(1)
+	jsonRPCCall := models.JsonRPCRequest{
+		JsonRPC: "2.0",
+		ID:      id,
+		Method:  call.Method,
+		Params:  call.Params,
+	}
+
+	callBytes, err := json.Marshal(jsonRPCCall)
+	if err != nil {
+		return err
+	}
+
+	p := &pendingCall{ch: make(chan []byte, 1), sub: sub, session: s}
+	w.mu.Lock()
+	w.pending[id] = p
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		if !p.orphaned {
+			delete(w.pending, id)
+		}
+		w.mu.Unlock()
+	}()
+
+	if err := s.write(callBytes); err != nil {
+		return err
+	}
+
+	var messageData []byte
+	select {
+	case messageData = <-p.ch:
+	case <-s.done:
+		// the response may have been read just before the connection was lost
+		select {
+		case messageData = <-p.ch:
+		default:
+			return ErrConnectionLost
+		}
+	case <-ctx.Done():
+		if sub == nil {
+			return ctx.Err()
+		}
+		w.mu.Lock()
+		_, waiting := w.pending[id]
+		p.orphaned = waiting
+		w.mu.Unlock()
+		if waiting {
+			return ctx.Err()
+		}
+		// the response was dispatched meanwhile and its subscription routed
+		messageData = <-p.ch
+	case <-w.ctx.Done():
+		return ErrClosed
+	}
+
+	return httpconn.DecodeResponse(bytes.NewReader(messageData), call.Method, call.Result)
 }
@@ -0,0 +1,410 @@
+package wsconn_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/common/sui_error"
+	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+var quietLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func waitState(t *testing.T, states <-chan wsconn.ConnState, want wsconn.ConnState) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case state := <-states:
+			if state == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for state %s", want)
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+
+	states := make(chan wsconn.ConnState, 16)
+	conn := wsconn.NewWsConnWithConfig(srv.WsURL, wsconn.Config{
+		Logger:        quietLogger,
+		Reconnect:     &wsconn.ReconnectPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+		OnStateChange: func(state wsconn.ConnState, err error) { states <- state },
+	})
+	defer conn.Close()
+	waitState(t, states, wsconn.StateConnected)
+
+	msgCh := make(chan []byte, 4)
+	err := conn.Call(context.Background(), wsconn.CallOp{
+		Method: "suix_subscribeEvent",
+		Params: []interface{}{map[string]interface{}{"All": []string{}}},
+	}, msgCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive := func() {
+		t.Helper()
+		select {
+		case <-msgCh:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a notification")
+		}
+	}
+	if n := srv.Notify("suix_subscribeEvent", map[string]interface{}{"type": "0x2::event::A"}); n != 1 {
+		t.Fatalf("expected 1 subscription, got %d", n)
+	}
+	receive()
+
+	srv.DropConnections()
+	waitState(t, states, wsconn.StateReconnecting)
+	waitState(t, states, wsconn.StateConnected)
+
+	// the subscription is issued again on the new connection
+	deadline := time.Now().Add(5 * time.Second)
+	for len(srv.Subscriptions()) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the subscription was not issued again")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := len(srv.CallsTo("suix_subscribeEvent")); n != 2 {
+		t.Errorf("expected 2 subscribe calls, got %d", n)
+	}
+	srv.Notify("suix_subscribeEvent", map[string]interface{}{"type": "0x2::event::B"})
+	receive()
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, states, wsconn.StateClosed)
+	err = conn.Call(context.Background(), wsconn.CallOp{Method: "suix_subscribeEvent"}, msgCh)
+	if !errors.Is(err, wsconn.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestReconnectGivesUp(t *testing.T) {
+	srv := suitest.NewServer()
+	url := srv.WsURL
+	srv.Close()
+
+	if _, err := wsconn.Dial(context.Background(), url, wsconn.Config{}); err == nil {
+		t.Error("expected Dial to fail")
+	}
+
+	errs := make(chan error, 1)
+	conn := wsconn.NewWsConnWithConfig(url, wsconn.Config{
+		Logger:    quietLogger,
+		Reconnect: &wsconn.ReconnectPolicy{InitialBackoff: time.Millisecond, MaxAttempts: 3},
+		OnStateChange: func(state wsconn.ConnState, err error) {
+			if state == wsconn.StateClosed {
+				errs <- err
+			}
+		},
+	})
+	select {
+	case err := <-errs:
+		if !errors.Is(err, wsconn.ErrReconnectFailed) {
+			t.Errorf("expected ErrReconnectFailed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the connection to close")
+	}
+	if state := conn.State(); state != wsconn.StateClosed {
+		t.Errorf("expected closed, got %s", state)
+	}
+}
+
+func TestMultiplexing(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	conn, err := wsconn.Dial(context.Background(), srv.WsURL, wsconn.Config{Logger: quietLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	// concurrent subscriptions on one connection receive their own notifications only
+	senders := []string{"0x1", "0x2"}
+	channels := make(map[string]chan []byte)
+	var wg sync.WaitGroup
+	for _, sender := range senders {
+		ch := make(chan []byte, 4)
+		channels[sender] = ch
+		wg.Add(1)
+		go func(sender string) {
+			defer wg.Done()
+			err := conn.Call(ctx, wsconn.CallOp{
+				Method: "suix_subscribeEvent",
+				Params: []interface{}{map[string]interface{}{"Sender": sender}},
+			}, ch)
+			if err != nil {
+				t.Error(err)
+			}
+		}(sender)
+	}
+	wg.Wait()
+
+	for _, sub := range srv.Subscriptions() {
+		var filters []map[string]string
+		if err := json.Unmarshal(sub.Params, &filters); err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.NotifySubscription(sub.ID, map[string]string{"sender": filters[0]["Sender"]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, sender := range senders {
+		select {
+		case data := <-channels[sender]:
+			var notification struct {
+				Params struct {
+					Result map[string]string `json:"result"`
+				} `json:"params"`
+			}
+			if err := json.Unmarshal(data, &notification); err != nil {
+				t.Fatal(err)
+			}
+			if got := notification.Params.Result["sender"]; got != sender {
+				t.Errorf("subscription of %s received the notification of %s", sender, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the notification of %s", sender)
+		}
+	}
+
+	// a slow call doesn't hold the others back
+	srv.SetLatency("sui_getChainIdentifier", 300*time.Millisecond)
+	slow := make(chan error, 1)
+	go func() {
+		var chain string
+		slow <- conn.CallContext(ctx, &chain, wsconn.CallOp{Method: "sui_getChainIdentifier"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	var price uint64
+	if err := conn.CallContext(ctx, &price, wsconn.CallOp{Method: "suix_getReferenceGasPrice"}); err != nil || price != 750 {
+		t.Fatalf("unexpected gas price %d, %v", price, err)
+	}
+	select {
+	case err := <-slow:
+		t.Fatalf("the slow call returned first, %v", err)
+	default:
+	}
+	if err := <-slow; err != nil {
+		t.Error(err)
+	}
+
+	err = conn.CallContext(ctx, nil, wsconn.CallOp{Method: "sui_unknownMethod"})
+	if !errors.Is(err, sui_error.ErrMethodNotFound) {
+		t.Errorf("expected ErrMethodNotFound, got %v", err)
+	}
+}
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	conn, err := wsconn.Dial(context.Background(), srv.WsURL, wsconn.Config{Logger: quietLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := wsconn.CallOp{Method: "suix_subscribeTransaction", Params: []interface{}{map[string]string{"FromAddress": suitest.Address}}}
+
+	// cancelling the context unsubscribes
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := conn.Subscribe(ctx, op, make(chan []byte))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	select {
+	case <-sub.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription didn't end with its context")
+	}
+	if err, ok := <-sub.Err(); ok {
+		t.Errorf("expected no error, got %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(srv.CallsTo("suix_unsubscribeTransaction")) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("suix_unsubscribeTransaction was not called")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := sub.Unsubscribe(); err != nil {
+		t.Errorf("expected a second Unsubscribe to be a no-op, got %v", err)
+	}
+
+	// closing the connection ends the subscriptions with ErrClosed
+	sub, err = conn.Subscribe(context.Background(), op, make(chan []byte))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a notification nobody receives doesn't hold the connection back once the subscription ended
+	srv.Notify("suix_subscribeTransaction", map[string]string{"status": "success"})
+	conn.Close()
+	select {
+	case err := <-sub.Err():
+		if !errors.Is(err, wsconn.ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription didn't end with the connection")
+	}
+	<-sub.Done()
+	if err := sub.Unsubscribe(); err != nil {
+		t.Errorf("expected Unsubscribe to be a no-op once closed, got %v", err)
+	}
+}
+
+func TestSubscribeTimeout(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	conn, err := wsconn.Dial(context.Background(), srv.WsURL, wsconn.Config{Logger: quietLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the node makes the subscription after the caller gave up, it's cancelled once the response arrives
+	srv.SetLatency("suix_subscribeEvent", 200*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	op := wsconn.CallOp{Method: "suix_subscribeEvent", Params: []interface{}{map[string]string{"Sender": suitest.Address}}}
+	if _, err := conn.Subscribe(ctx, op, make(chan []byte)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the subscribe call to time out, got %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(srv.CallsTo("suix_unsubscribeEvent")) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the orphaned subscription was not cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if subs := srv.Subscriptions(); len(subs) != 0 {
+		t.Errorf("expected no subscription left on the node, got %v", subs)
+	}
+}
+
+func TestKeepalive(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	conn, err := wsconn.Dial(context.Background(), srv.WsURL, wsconn.Config{
+		Logger:    quietLogger,
+		Keepalive: &wsconn.KeepalivePolicy{PingInterval: 10 * time.Millisecond, IdleTimeout: time.Second},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for conn.Health().RTT == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no pong was received")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if health := conn.Health(); health.State != wsconn.StateConnected || health.SinceLastMessage > time.Second {
+		t.Errorf("unexpected health %+v", health)
+	}
+
+	// a node that stops answering, like the far end of a half-open connection, is dialed again
+	upgrader := websocket.Upgrader{}
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		<-r.Context().Done()
+	}))
+	defer stalled.Close()
+	defer stalled.CloseClientConnections()
+
+	states := make(chan wsconn.ConnState, 16)
+	errs := make(chan error, 16)
+	conn, err = wsconn.Dial(context.Background(), "ws"+strings.TrimPrefix(stalled.URL, "http"), wsconn.Config{
+		Logger:    quietLogger,
+		Reconnect: &wsconn.ReconnectPolicy{InitialBackoff: 10 * time.Millisecond},
+		Keepalive: &wsconn.KeepalivePolicy{PingInterval: 10 * time.Millisecond, IdleTimeout: 50 * time.Millisecond},
+		OnStateChange: func(state wsconn.ConnState, err error) {
+			if state == wsconn.StateReconnecting {
+				errs <- err
+			}
+			states <- state
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	waitState(t, states, wsconn.StateConnected)
+	waitState(t, states, wsconn.StateReconnecting)
+	if err := <-errs; !errors.Is(err, wsconn.ErrConnectionIdle) {
+		t.Errorf("expected ErrConnectionIdle, got %v", err)
+	}
+	waitState(t, states, wsconn.StateConnected)
+	if n := conn.Health().Reconnects; n == 0 {
+		t.Error("expected the reconnection to be counted")
+	}
+}
+
+func TestCallDecoding(t *testing.T) {
+	// the node answers sui_empty without result and the others with a u64 encoded as a string
+	upgrader := websocket.Upgrader{}
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		for {
+			var req struct {
+				ID     json.RawMessage `json:"id"`
+				Method string          `json:"method"`
+			}
+			if err := c.ReadJSON(&req); err != nil {
+				return
+			}
+			rsp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			if req.Method != "sui_empty" {
+				rsp["result"] = "25000000"
+			}
+			if err := c.WriteJSON(rsp); err != nil {
+				return
+			}
+		}
+	}))
+	defer node.Close()
+	defer node.CloseClientConnections()
+
+	conn, err := wsconn.Dial(context.Background(), "ws"+strings.TrimPrefix(node.URL, "http"), wsconn.Config{Logger: quietLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var checkpoint uint64
+	if err := conn.CallContext(context.Background(), &checkpoint, wsconn.CallOp{Method: "sui_getLatestCheckpointSequenceNumber"}); err != nil || checkpoint != 25000000 {
+		t.Errorf("unexpected checkpoint %d, %v", checkpoint, err)
+	}
+	if err := conn.CallContext(context.Background(), &checkpoint, wsconn.CallOp{Method: "sui_empty"}); !errors.Is(err, httpconn.ErrNoResult) {
+		t.Errorf("expected ErrNoResult as over HTTP, got %v", err)
+	}
+}
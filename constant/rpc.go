@@ -13,3 +13,8 @@ const (
 
 	SuiPublicTestnet = "https://fullnode.testnet.sui.io:443"
 )
+
+var (
+	MainnetEndpoints = []string{BvMainnetEndpoint, SuiMainnetEndpoint}
+	TestnetEndpoints = []string{BvTestnetEndpoint, SuiTestnetEndpoint}
+)
@@ -20,7 +20,7 @@ func SubscribeEvent() {
 	var cli = sui.NewSuiWebsocketClient(constant.WssBvTestnetEndpoint)
 
 	receiveMsgCh := make(chan models.SuiEventResponse, 10)
-	err := cli.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{
+	sub, err := cli.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{
 		SuiEventFilter: map[string]interface{}{
 			"All": []string{},
 		},
@@ -28,12 +28,16 @@ func SubscribeEvent() {
 	if err != nil {
 		panic(err)
 	}
+	defer sub.Unsubscribe()
 
 	for {
 		select {
 		case msg := <-receiveMsgCh:
 			utils.PrettyPrint(msg)
-		case <-ctx.Done():
+		case err := <-sub.Err():
+			if err != nil {
+				panic(err)
+			}
 			return
 		}
 	}
@@ -44,7 +48,7 @@ func SubscribeTransaction() {
 	var cli = sui.NewSuiWebsocketClient(constant.WssBvTestnetEndpoint)
 
 	receiveMsgCh := make(chan models.SuiEffects, 10)
-	err := cli.SubscribeTransaction(ctx, models.SuiXSubscribeTransactionsRequest{
+	sub, err := cli.SubscribeTransaction(ctx, models.SuiXSubscribeTransactionsRequest{
 		TransactionFilter: models.TransactionFilterByFromAddress{
 			FromAddress: "0x0000000000000000000000000000000000000000000000000000000000000000",
 		},
@@ -52,12 +56,16 @@ func SubscribeTransaction() {
 	if err != nil {
 		panic(err)
 	}
+	defer sub.Unsubscribe()
 
 	for {
 		select {
 		case msg := <-receiveMsgCh:
 			utils.PrettyPrint(msg)
-		case <-ctx.Done():
+		case err := <-sub.Err():
+			if err != nil {
+				panic(err)
+			}
 			return
 		}
 	}
@@ -1,6 +1,6 @@
 module github.com/yasir7ca/sui-go-sdk
 
-go 1.20
+go 1.23
 
 require (
 	github.com/go-playground/validator/v10 v10.12.0
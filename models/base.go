@@ -2,7 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+
+	"github.com/yasir7ca/sui-go-sdk/common/sui_error"
 )
 
 var null = json.RawMessage("null")
@@ -21,27 +24,49 @@ type JsonRPCMessage struct {
 	ID      json.RawMessage `json:"id,omitempty"`
 	Method  string          `json:"method,omitempty"`
 	Params  json.RawMessage `json:"params,omitempty"`
-	Error   *jsonError      `json:"error,omitempty"`
+	Error   *JsonRPCError   `json:"error,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
 }
 
-type jsonError struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+// JsonRPCError is the error object of a JSON-RPC response. Method and RequestID are filled in by the client
+// that made the call.
+//
+// It matches the sentinel errors of sui_error with errors.Is, e.g.
+//
+//	if errors.Is(err, sui_error.ErrObjectLocked) { ... }
+type JsonRPCError struct {
+	Code      int             `json:"code"`
+	Message   string          `json:"message"`
+	Data      interface{}     `json:"data,omitempty"`
+	Method    string          `json:"-"`
+	RequestID json.RawMessage `json:"-"`
 }
 
-func (err *jsonError) Error() string {
+func (err *JsonRPCError) Error() string {
 	if err.Message == "" {
 		return fmt.Sprintf("json-rpc error %d", err.Code)
 	}
 	return err.Message
 }
 
-func (err *jsonError) ErrorCode() int {
+func (err *JsonRPCError) ErrorCode() int {
 	return err.Code
 }
 
-func (err *jsonError) ErrorData() interface{} {
+func (err *JsonRPCError) ErrorData() interface{} {
 	return err.Data
 }
+
+// Is reports whether target is one of the sui_error sentinels matching the code, message or data of err.
+func (err *JsonRPCError) Is(target error) bool {
+	text := err.Message
+	if err.Data != nil {
+		text += fmt.Sprint(" ", err.Data)
+	}
+	for _, e := range sui_error.Classify(err.Code, text) {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
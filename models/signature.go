@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/kms"
 	crypto_etherium "github.com/ethereum/go-ethereum/crypto"
+	"github.com/yasir7ca/sui-go-sdk/utils"
 	"golang.org/x/crypto/blake2b"
 )
 
@@ -178,6 +179,17 @@ func (txn *TxnMetaData) SignSerializedSigWithKMS(keyId string, svc *kms.KMS, pub
 	return toSerializedSignatureKMS(signature, publicKey), nil
 }
 
+// ComputeTransactionDigest returns the base58 digest of base64 encoded transaction data bytes,
+// which is the digest the transaction is known by once executed.
+func ComputeTransactionDigest(txBytes string) (TransactionDigest, error) {
+	data, err := base64.StdEncoding.DecodeString(txBytes)
+	if err != nil {
+		return "", err
+	}
+	digest := blake2b.Sum256(append([]byte("TransactionData::"), data...))
+	return TransactionDigest(utils.Base58Encode(digest[:])), nil
+}
+
 func messageWithIntent(message []byte) []byte {
 	intent := IntentBytes
 	intentMessage := make([]byte, len(intent)+len(message))
@@ -20,6 +20,7 @@ type ISuiAPI interface {
 	IReadSystemFromSuiAPI
 	IReadMoveFromSuiAPI
 	IReadNameServiceFromSuiAPI
+	ICheckpointStreamAPI
 }
 
 // Client implements SuiAPI related interfaces.
@@ -33,48 +34,76 @@ type Client struct {
 	IReadSystemFromSuiAPI
 	IReadMoveFromSuiAPI
 	IReadNameServiceFromSuiAPI
+	ICheckpointStreamAPI
 }
 
 // NewSuiClient instantiates the Sui client to call the methods of each module.
-func NewSuiClient(rpcUrl string) ISuiAPI {
-	conn := httpconn.Dial(rpcUrl)
-	return newClient(conn)
+// The HTTP transport is configured by opts, e.g. WithTimeout, WithRetryPolicy or WithInterceptors.
+func NewSuiClient(rpcUrl string, opts ...Option) ISuiAPI {
+	options := newClientOptions(opts)
+	var conn *httpconn.HttpConn
+	if options.pool != nil {
+		conn = httpconn.DialPoolWithConfig(options.pool, options.http)
+	} else {
+		conn = httpconn.DialWithConfig(rpcUrl, options.http)
+	}
+	return newClient(conn, options)
 }
 
 // NewSuiClientWithCustomClient custom HTTP client, instantiates the Sui client to call the methods of each module.
 func NewSuiClientWithCustomClient(rpcUrl string, c *http.Client) ISuiAPI {
-	conn := httpconn.DialWithClient(rpcUrl, c)
-	return newClient(conn)
+	return NewSuiClient(rpcUrl, WithHTTPClient(c))
+}
+
+// NewSuiClientWithTransport instantiates the Sui client performing its calls on t, e.g. the websocket connection
+// of NewWebsocketTransport. The options configuring a transport are ignored, the others apply.
+func NewSuiClientWithTransport(t Transport, opts ...Option) ISuiAPI {
+	return newClient(t, newClientOptions(opts))
 }
 
-func newClient(conn *httpconn.HttpConn) *Client {
+func newClient(conn Transport, options *clientOptions) *Client {
+	readTransaction := &suiReadTransactionFromSuiImpl{
+		conn:    conn,
+		options: options,
+	}
+	readSystem := &suiReadSystemFromSuiImpl{
+		conn: conn,
+	}
+	write := &suiWriteTransactionImpl{
+		conn:    conn,
+		options: options,
+	}
+	if options.gasEstimate != nil {
+		write.gasEstimator = &GasEstimator{transaction: readTransaction, system: readSystem, config: options.gasEstimate.withDefaults()}
+	}
 	return &Client{
 		IBaseAPI: &suiBaseImpl{
-			conn: conn,
+			conn:    conn,
+			options: options,
 		},
 		IReadCoinFromSuiAPI: &suiReadCoinFromSuiImpl{
 			conn: conn,
 		},
-		IWriteTransactionAPI: &suiWriteTransactionImpl{
-			conn: conn,
-		},
+		IWriteTransactionAPI: write,
 		IReadEventFromSuiAPI: &suiReadEventFromSuiImpl{
 			conn: conn,
 		},
 		IReadObjectFromSuiAPI: &suiReadObjectFromSuiImpl{
-			conn: conn,
-		},
-		IReadTransactionFromSuiAPI: &suiReadTransactionFromSuiImpl{
-			conn: conn,
-		},
-		IReadSystemFromSuiAPI: &suiReadSystemFromSuiImpl{
-			conn: conn,
+			conn:    conn,
+			options: options,
 		},
+		IReadTransactionFromSuiAPI: readTransaction,
+		IReadSystemFromSuiAPI:      readSystem,
 		IReadMoveFromSuiAPI: &suiReadMoveFromSuiImpl{
 			conn: conn,
 		},
 		IReadNameServiceFromSuiAPI: &suiReadNameServiceFromSuiImpl{
 			conn: conn,
 		},
+		ICheckpointStreamAPI: &suiCheckpointStreamImpl{
+			conn:        conn,
+			system:      readSystem,
+			transaction: readTransaction,
+		},
 	}
 }
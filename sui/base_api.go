@@ -11,10 +11,13 @@ import (
 
 type IBaseAPI interface {
 	SuiCall(ctx context.Context, method string, params ...interface{}) (interface{}, error)
+	SuiBatchCall(ctx context.Context, batch []httpconn.BatchElem) error
+	NewBatch() *Batch
 }
 
 type suiBaseImpl struct {
-	conn *httpconn.HttpConn
+	conn    Transport
+	options *clientOptions
 }
 
 // SuiCall send customized request to Sui Node endpoint.
@@ -29,3 +32,14 @@ func (s *suiBaseImpl) SuiCall(ctx context.Context, method string, params ...inte
 	}
 	return resp, nil
 }
+
+// SuiBatchCall send customized requests to Sui Node endpoint in a single JSON-RPC batch.
+// Errors of individual calls are reported through the Error field of each element.
+func (s *suiBaseImpl) SuiBatchCall(ctx context.Context, batch []httpconn.BatchElem) error {
+	return s.conn.BatchCallContext(ctx, batch)
+}
+
+// NewBatch creates an empty Batch bound to the client's connection.
+func (s *suiBaseImpl) NewBatch() *Batch {
+	return &Batch{conn: s.conn, options: s.options}
+}
@@ -0,0 +1,241 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+var (
+	ErrBatchNotSent     = errors.New("batch has not been sent yet")
+	ErrBatchAlreadySent = errors.New("batch has already been sent")
+)
+
+// Batch collects JSON-RPC calls and sends them to the Sui node in a single HTTP request.
+// Calls are queued with the typed helpers (e.g. Batch.SuiXGetBalance) or AddBatchCall,
+// sent with Send, and then read back through the returned BatchResult values.
+// Over a websocket transport the calls are sent concurrently on the connection instead.
+type Batch struct {
+	conn    Transport
+	options *clientOptions
+	elems   []httpconn.BatchElem
+	sent    bool
+}
+
+// BatchResult is the pending result of a call queued in a Batch.
+type BatchResult[T any] struct {
+	batch *Batch
+	index int
+	value T
+	err   error
+}
+
+// Result returns the decoded response of the call, or the error reported for it.
+func (r *BatchResult[T]) Result() (T, error) {
+	if r.err != nil {
+		return r.value, r.err
+	}
+	if !r.batch.sent {
+		return r.value, ErrBatchNotSent
+	}
+	if err := r.batch.elems[r.index].Error; err != nil {
+		return r.value, err
+	}
+	return r.value, nil
+}
+
+// AddBatchCall queues a call of the given method and decodes its response into T once the batch is sent.
+func AddBatchCall[T any](b *Batch, method string, params ...interface{}) *BatchResult[T] {
+	r := &BatchResult[T]{batch: b}
+	if b.sent {
+		r.err = ErrBatchAlreadySent
+		return r
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+	r.index = len(b.elems)
+	b.elems = append(b.elems, httpconn.BatchElem{
+		Method: method,
+		Params: params,
+		Result: &r.value,
+	})
+	return r
+}
+
+// failedBatchCall returns a result that carries err without queueing any call, it's used when request validation fails.
+func failedBatchCall[T any](b *Batch, err error) *BatchResult[T] {
+	return &BatchResult[T]{batch: b, err: err}
+}
+
+// Len returns the number of calls queued in the batch.
+func (b *Batch) Len() int {
+	return len(b.elems)
+}
+
+// Send sends all queued calls in a single request. A batch can only be sent once.
+// The returned error only reports transport failures, errors of individual calls are reported by their BatchResult.
+func (b *Batch) Send(ctx context.Context) error {
+	if b.sent {
+		return ErrBatchAlreadySent
+	}
+	if err := b.conn.BatchCallContext(ctx, b.elems); err != nil {
+		return err
+	}
+	b.sent = true
+	return nil
+}
+
+// SuiXGetBalance queues the method `suix_getBalance`.
+func (b *Batch) SuiXGetBalance(req models.SuiXGetBalanceRequest) *BatchResult[models.CoinBalanceResponse] {
+	return AddBatchCall[models.CoinBalanceResponse](b, "suix_getBalance", req.Owner, req.CoinType)
+}
+
+// SuiXGetAllBalance queues the method `suix_getAllBalances`.
+func (b *Batch) SuiXGetAllBalance(req models.SuiXGetAllBalanceRequest) *BatchResult[models.CoinAllBalanceResponse] {
+	return AddBatchCall[models.CoinAllBalanceResponse](b, "suix_getAllBalances", req.Owner)
+}
+
+// SuiXGetCoins queues the method `suix_getCoins`.
+func (b *Batch) SuiXGetCoins(req models.SuiXGetCoinsRequest) *BatchResult[models.PaginatedCoinsResponse] {
+	if err := validate.ValidateStruct(req); err != nil {
+		return failedBatchCall[models.PaginatedCoinsResponse](b, err)
+	}
+	return AddBatchCall[models.PaginatedCoinsResponse](b, "suix_getCoins", req.Owner, req.CoinType, req.Cursor, req.Limit)
+}
+
+// SuiXGetAllCoins queues the method `suix_getAllCoins`.
+func (b *Batch) SuiXGetAllCoins(req models.SuiXGetAllCoinsRequest) *BatchResult[models.PaginatedCoinsResponse] {
+	if err := validate.ValidateStruct(req); err != nil {
+		return failedBatchCall[models.PaginatedCoinsResponse](b, err)
+	}
+	return AddBatchCall[models.PaginatedCoinsResponse](b, "suix_getAllCoins", req.Owner, req.Cursor, req.Limit)
+}
+
+// SuiXGetCoinMetadata queues the method `suix_getCoinMetadata`.
+func (b *Batch) SuiXGetCoinMetadata(req models.SuiXGetCoinMetadataRequest) *BatchResult[models.CoinMetadataResponse] {
+	return AddBatchCall[models.CoinMetadataResponse](b, "suix_getCoinMetadata", req.CoinType)
+}
+
+// SuiXGetTotalSupply queues the method `suix_getTotalSupply`.
+func (b *Batch) SuiXGetTotalSupply(req models.SuiXGetTotalSupplyRequest) *BatchResult[models.TotalSupplyResponse] {
+	return AddBatchCall[models.TotalSupplyResponse](b, "suix_getTotalSupply", req.CoinType)
+}
+
+// SuiGetObject queues the method `sui_getObject`.
+func (b *Batch) SuiGetObject(req models.SuiGetObjectRequest) *BatchResult[models.SuiObjectData] {
+	return AddBatchCall[models.SuiObjectData](b, "sui_getObject", req.ObjectId, b.options.objectOptions(req.Options))
+}
+
+// SuiXGetOwnedObjects queues the method `suix_getOwnedObjects`.
+func (b *Batch) SuiXGetOwnedObjects(req models.SuiXGetOwnedObjectsRequest) *BatchResult[models.PaginatedObjectsResponse] {
+	if err := validate.ValidateStruct(req); err != nil {
+		return failedBatchCall[models.PaginatedObjectsResponse](b, err)
+	}
+	req.Query.Options = b.options.objectOptions(req.Query.Options)
+	return AddBatchCall[models.PaginatedObjectsResponse](b, "suix_getOwnedObjects", req.Address, req.Query, req.Cursor, req.Limit)
+}
+
+// SuiMultiGetObjects queues the method `sui_multiGetObjects`.
+func (b *Batch) SuiMultiGetObjects(req models.SuiMultiGetObjectsRequest) *BatchResult[[]*models.SuiObjectResponse] {
+	return AddBatchCall[[]*models.SuiObjectResponse](b, "sui_multiGetObjects", req.ObjectIds, b.options.objectOptions(req.Options))
+}
+
+// SuiXGetDynamicField queues the method `suix_getDynamicFields`.
+func (b *Batch) SuiXGetDynamicField(req models.SuiXGetDynamicFieldRequest) *BatchResult[models.PaginatedDynamicFieldInfoResponse] {
+	if err := validate.ValidateStruct(req); err != nil {
+		return failedBatchCall[models.PaginatedDynamicFieldInfoResponse](b, err)
+	}
+	return AddBatchCall[models.PaginatedDynamicFieldInfoResponse](b, "suix_getDynamicFields", req.ObjectId, req.Cursor, req.Limit)
+}
+
+// SuiXGetDynamicFieldObject queues the method `suix_getDynamicFieldObject`.
+func (b *Batch) SuiXGetDynamicFieldObject(req models.SuiXGetDynamicFieldObjectRequest) *BatchResult[models.SuiObjectResponse] {
+	return AddBatchCall[models.SuiObjectResponse](b, "suix_getDynamicFieldObject", req.ObjectId, req.DynamicFieldName)
+}
+
+// SuiTryGetPastObject queues the method `sui_tryGetPastObject`.
+func (b *Batch) SuiTryGetPastObject(req models.SuiTryGetPastObjectRequest) *BatchResult[models.PastObjectResponse] {
+	return AddBatchCall[models.PastObjectResponse](b, "sui_tryGetPastObject", req.ObjectId, req.Version, b.options.objectOptions(req.Options))
+}
+
+// SuiGetTransactionBlock queues the method `sui_getTransactionBlock`.
+func (b *Batch) SuiGetTransactionBlock(req models.SuiGetTransactionBlockRequest) *BatchResult[models.SuiTransactionBlockResponse] {
+	return AddBatchCall[models.SuiTransactionBlockResponse](b, "sui_getTransactionBlock", req.Digest, b.options.transactionOptions(req.Options))
+}
+
+// SuiMultiGetTransactionBlocks queues the method `sui_multiGetTransactionBlocks`.
+func (b *Batch) SuiMultiGetTransactionBlocks(req models.SuiMultiGetTransactionBlocksRequest) *BatchResult[models.SuiMultiGetTransactionBlocksResponse] {
+	return AddBatchCall[models.SuiMultiGetTransactionBlocksResponse](b, "sui_multiGetTransactionBlocks", req.Digests, b.options.transactionOptions(req.Options))
+}
+
+// SuiXQueryTransactionBlocks queues the method `suix_queryTransactionBlocks`.
+func (b *Batch) SuiXQueryTransactionBlocks(req models.SuiXQueryTransactionBlocksRequest) *BatchResult[models.SuiXQueryTransactionBlocksResponse] {
+	if err := validate.ValidateStruct(req); err != nil {
+		return failedBatchCall[models.SuiXQueryTransactionBlocksResponse](b, err)
+	}
+	query := req.SuiTransactionBlockResponseQuery
+	query.Options = b.options.transactionOptions(query.Options)
+	return AddBatchCall[models.SuiXQueryTransactionBlocksResponse](b, "suix_queryTransactionBlocks", query, req.Cursor, req.Limit, req.DescendingOrder)
+}
+
+// SuiGetEvents queues the method `sui_getEvents`.
+func (b *Batch) SuiGetEvents(req models.SuiGetEventsRequest) *BatchResult[models.GetEventsResponse] {
+	return AddBatchCall[models.GetEventsResponse](b, "sui_getEvents", req.Digest)
+}
+
+// SuiXQueryEvents queues the method `suix_queryEvents`.
+func (b *Batch) SuiXQueryEvents(req models.SuiXQueryEventsRequest) *BatchResult[models.PaginatedEventsResponse] {
+	if err := validate.ValidateStruct(req); err != nil {
+		return failedBatchCall[models.PaginatedEventsResponse](b, err)
+	}
+	return AddBatchCall[models.PaginatedEventsResponse](b, "suix_queryEvents", req.SuiEventFilter, req.Cursor, req.Limit, req.DescendingOrder)
+}
+
+// SuiGetCheckpoint queues the method `sui_getCheckpoint`.
+func (b *Batch) SuiGetCheckpoint(req models.SuiGetCheckpointRequest) *BatchResult[models.CheckpointResponse] {
+	return AddBatchCall[models.CheckpointResponse](b, "sui_getCheckpoint", req.CheckpointID)
+}
+
+// SuiGetCheckpoints queues the method `sui_getCheckpoints`.
+func (b *Batch) SuiGetCheckpoints(req models.SuiGetCheckpointsRequest) *BatchResult[models.PaginatedCheckpointsResponse] {
+	if err := validate.ValidateStruct(req); err != nil {
+		return failedBatchCall[models.PaginatedCheckpointsResponse](b, err)
+	}
+	return AddBatchCall[models.PaginatedCheckpointsResponse](b, "sui_getCheckpoints", req.Cursor, req.Limit, req.DescendingOrder)
+}
+
+// SuiGetLatestCheckpointSequenceNumber queues the method `sui_getLatestCheckpointSequenceNumber`.
+func (b *Batch) SuiGetLatestCheckpointSequenceNumber() *BatchResult[uint64] {
+	return AddBatchCall[uint64](b, "sui_getLatestCheckpointSequenceNumber")
+}
+
+// SuiXGetReferenceGasPrice queues the method `suix_getReferenceGasPrice`.
+func (b *Batch) SuiXGetReferenceGasPrice() *BatchResult[uint64] {
+	return AddBatchCall[uint64](b, "suix_getReferenceGasPrice")
+}
+
+// SuiXGetStakes queues the method `suix_getStakes`.
+func (b *Batch) SuiXGetStakes(req models.SuiXGetStakesRequest) *BatchResult[[]*models.DelegatedStakesResponse] {
+	return AddBatchCall[[]*models.DelegatedStakesResponse](b, "suix_getStakes", req.Owner)
+}
+
+// SuiXGetCurrentEpoch queues the method `suix_getCurrentEpoch`.
+func (b *Batch) SuiXGetCurrentEpoch() *BatchResult[models.EpochInfo] {
+	return AddBatchCall[models.EpochInfo](b, "suix_getCurrentEpoch")
+}
+
+// SuiXGetLatestSuiSystemState queues the method `suix_getLatestSuiSystemState`.
+func (b *Batch) SuiXGetLatestSuiSystemState() *BatchResult[models.SuiSystemStateSummary] {
+	return AddBatchCall[models.SuiSystemStateSummary](b, "suix_getLatestSuiSystemState")
+}
+
+// SuiGetChainIdentifier queues the method `sui_getChainIdentifier`.
+func (b *Batch) SuiGetChainIdentifier() *BatchResult[string] {
+	return AddBatchCall[string](b, "sui_getChainIdentifier")
+}
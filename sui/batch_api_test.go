@@ -0,0 +1,84 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yasir7ca/sui-go-sdk/common/sui_error"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+func TestBatch(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	srv.SetError("suix_getCoinMetadata", sui_error.CodeInvalidParams, "unknown coin type")
+
+	// the node answers the calls of a batch in reverse order, they are matched by id
+	var requests atomic.Int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		rsp, err := http.Post(srv.URL, "application/json", r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer rsp.Body.Close()
+		var rsps []json.RawMessage
+		if err := json.NewDecoder(rsp.Body).Decode(&rsps); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		slices.Reverse(rsps)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(rsps)
+	}))
+	defer node.Close()
+
+	batch := NewSuiClient(node.URL).NewBatch()
+	balance := batch.SuiXGetBalance(models.SuiXGetBalanceRequest{Owner: suitest.Address, CoinType: "0x2::sui::SUI"})
+	chain := AddBatchCall[string](batch, "sui_getChainIdentifier")
+	metadata := batch.SuiXGetCoinMetadata(models.SuiXGetCoinMetadataRequest{CoinType: "0x2::unknown::COIN"})
+	gasPrice := batch.SuiXGetReferenceGasPrice()
+	if batch.Len() != 4 {
+		t.Fatalf("expected 4 calls, got %d", batch.Len())
+	}
+	if _, err := balance.Result(); !errors.Is(err, ErrBatchNotSent) {
+		t.Errorf("expected ErrBatchNotSent, got %v", err)
+	}
+
+	if err := batch.Send(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected a single request, got %d", n)
+	}
+	if rsp, err := balance.Result(); err != nil || rsp.TotalBalance != "1000000000" {
+		t.Errorf("unexpected balance %+v, %v", rsp, err)
+	}
+	if rsp, err := chain.Result(); err != nil || rsp != suitest.ChainIdentifier {
+		t.Errorf("unexpected chain identifier %q, %v", rsp, err)
+	}
+	var rpcErr *models.JsonRPCError
+	if _, err := metadata.Result(); !errors.As(err, &rpcErr) || rpcErr.Code != sui_error.CodeInvalidParams || rpcErr.Method != "suix_getCoinMetadata" {
+		t.Errorf("expected the error of suix_getCoinMetadata, got %v", err)
+	}
+	if rsp, err := gasPrice.Result(); err != nil || rsp != 750 {
+		t.Errorf("unexpected reference gas price %d, %v", rsp, err)
+	}
+
+	if err := batch.Send(ctx); !errors.Is(err, ErrBatchAlreadySent) {
+		t.Errorf("expected ErrBatchAlreadySent, got %v", err)
+	}
+	if _, err := batch.SuiXGetReferenceGasPrice().Result(); !errors.Is(err, ErrBatchAlreadySent) {
+		t.Errorf("expected ErrBatchAlreadySent, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+var (
+	ErrCheckpointMissing = errors.New("checkpoint missing from the node")
+)
+
+// multiGetTransactionBlocksLimit is the maximum number of digests of a `sui_multiGetTransactionBlocks` call.
+const multiGetTransactionBlocksLimit = 50
+
+// maxTipPollBackoff caps the delay between two polls of the latest checkpoint failing transiently.
+const maxTipPollBackoff = 30 * time.Second
+
+type ICheckpointStreamAPI interface {
+	StreamCheckpoints(ctx context.Context, fromSeq uint64, fn func(Checkpoint) error, opts ...CheckpointStreamOption) error
+}
+
+type suiCheckpointStreamImpl struct {
+	conn        Transport
+	system      IReadSystemFromSuiAPI
+	transaction IReadTransactionFromSuiAPI
+}
+
+// Checkpoint is a checkpoint delivered by StreamCheckpoints.
+type Checkpoint struct {
+	models.CheckpointResponse
+	// Transactions are the transactions of the checkpoint in execution order, fetched with
+	// WithCheckpointTransactions only.
+	Transactions []*models.SuiTransactionBlockResponse
+}
+
+// CheckpointStreamOption configures StreamCheckpoints.
+type CheckpointStreamOption func(*checkpointStreamOptions)
+
+type checkpointStreamOptions struct {
+	fetchAhead   int
+	pageSize     uint64
+	pollInterval time.Duration
+	transactions *models.SuiTransactionBlockOptions
+}
+
+// WithCheckpointFetchAhead is the number of pages of checkpoints fetched in parallel ahead of the one being
+// delivered, 4 by default.
+func WithCheckpointFetchAhead(pages int) CheckpointStreamOption {
+	return func(o *checkpointStreamOptions) {
+		o.fetchAhead = pages
+	}
+}
+
+// WithCheckpointPageSize is the number of checkpoints fetched per `sui_getCheckpoints` call, up to 50, 50 by default.
+func WithCheckpointPageSize(size uint64) CheckpointStreamOption {
+	return func(o *checkpointStreamOptions) {
+		o.pageSize = size
+	}
+}
+
+// WithCheckpointPollInterval is the delay between two polls of the latest checkpoint once the stream caught up
+// with the tip of the chain, 1s by default.
+func WithCheckpointPollInterval(interval time.Duration) CheckpointStreamOption {
+	return func(o *checkpointStreamOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithCheckpointTransactions attaches the transactions of every checkpoint, fetched with options through
+// `sui_multiGetTransactionBlocks` in chunks of 50 digests.
+func WithCheckpointTransactions(options models.SuiTransactionBlockOptions) CheckpointStreamOption {
+	return func(o *checkpointStreamOptions) {
+		o.transactions = &options
+	}
+}
+
+func newCheckpointStreamOptions(opts []CheckpointStreamOption) checkpointStreamOptions {
+	o := checkpointStreamOptions{fetchAhead: 4, pageSize: 50, pollInterval: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.fetchAhead <= 0 {
+		o.fetchAhead = 1
+	}
+	if o.pageSize == 0 || o.pageSize > 50 {
+		o.pageSize = 50
+	}
+	if o.pollInterval <= 0 {
+		o.pollInterval = time.Second
+	}
+	return o
+}
+
+// checkpointPage is the result of fetching the checkpoints from first to last.
+type checkpointPage struct {
+	first, last uint64
+	checkpoints []Checkpoint
+	err         error
+}
+
+// StreamCheckpoints passes the checkpoints from fromSeq on to fn, one at a time and strictly in sequence order,
+// then follows the tip of the chain by polling `sui_getLatestCheckpointSequenceNumber`. Pages of checkpoints are
+// fetched in parallel through `sui_getCheckpoints`, the checkpoints missing from a page are fetched one by one
+// through `sui_getCheckpoint`.
+// It returns when ctx is done, fn returns an error or a checkpoint can't be fetched, with that error. Once caught up,
+// the transient failures of the poll are retried with an exponential backoff from the poll interval.
+func (s *suiCheckpointStreamImpl) StreamCheckpoints(ctx context.Context, fromSeq uint64, fn func(Checkpoint) error, opts ...CheckpointStreamOption) error {
+	options := newCheckpointStreamOptions(opts)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tip, err := s.system.SuiGetLatestCheckpointSequenceNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the fetchers never block on results, at most fetchAhead pages are in flight or waiting to be delivered
+	results := make(chan checkpointPage, options.fetchAhead)
+	pending := make(map[uint64]checkpointPage)
+	next, nextFetch, inFlight := fromSeq, fromSeq, 0
+	for {
+		for inFlight < options.fetchAhead && nextFetch <= tip {
+			last := min(nextFetch+options.pageSize-1, tip)
+			go func(first, last uint64) {
+				checkpoints, err := s.fetchPage(ctx, first, last, options)
+				results <- checkpointPage{first: first, last: last, checkpoints: checkpoints, err: err}
+			}(nextFetch, last)
+			inFlight++
+			nextFetch = last + 1
+		}
+
+		if inFlight == 0 {
+			// caught up with the tip
+			latest, err := s.pollTip(ctx, options.pollInterval)
+			if err != nil {
+				return err
+			}
+			if latest >= nextFetch {
+				tip = latest
+				continue
+			}
+			select {
+			case <-time.After(options.pollInterval):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		select {
+		case page := <-results:
+			if page.err != nil {
+				return page.err
+			}
+			pending[page.first] = page
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		for page, ok := pending[next]; ok; page, ok = pending[next] {
+			for _, checkpoint := range page.checkpoints {
+				if err := fn(checkpoint); err != nil {
+					return err
+				}
+			}
+			delete(pending, next)
+			inFlight--
+			next = page.last + 1
+		}
+	}
+}
+
+// pollTip returns the latest checkpoint, retrying the transient failures until ctx is done.
+func (s *suiCheckpointStreamImpl) pollTip(ctx context.Context, interval time.Duration) (uint64, error) {
+	policy := s.conn.RetryPolicy()
+	for attempt := 1; ; attempt++ {
+		latest, err := s.system.SuiGetLatestCheckpointSequenceNumber(ctx)
+		if err == nil || ctx.Err() != nil || !policy.IsTransient(err) {
+			return latest, err
+		}
+		select {
+		case <-time.After(min(httpconn.ExponentialBackoff(attempt, interval, 2, 0.2), maxTipPollBackoff)):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// fetchPage fetches the checkpoints from first to last, the ones missing from the page are fetched one by one.
+func (s *suiCheckpointStreamImpl) fetchPage(ctx context.Context, first, last uint64, options checkpointStreamOptions) ([]Checkpoint, error) {
+	var cursor interface{}
+	if first > 0 {
+		cursor = strconv.FormatUint(first-1, 10)
+	}
+	rsp, err := s.system.SuiGetCheckpoints(ctx, models.SuiGetCheckpointsRequest{
+		Cursor: cursor,
+		Limit:  last - first + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bySeq := make(map[uint64]models.CheckpointResponse, len(rsp.Data))
+	for _, checkpoint := range rsp.Data {
+		seq, err := strconv.ParseUint(checkpoint.SequenceNumber, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint sequence number %q: %w", checkpoint.SequenceNumber, err)
+		}
+		bySeq[seq] = checkpoint
+	}
+
+	checkpoints := make([]Checkpoint, 0, last-first+1)
+	for seq := first; seq <= last; seq++ {
+		checkpoint, ok := bySeq[seq]
+		if !ok {
+			if checkpoint, err = s.fetchCheckpoint(ctx, seq); err != nil {
+				return nil, err
+			}
+		}
+		cp := Checkpoint{CheckpointResponse: checkpoint}
+		if options.transactions != nil {
+			if cp.Transactions, err = s.fetchTransactions(ctx, checkpoint.Transactions, *options.transactions); err != nil {
+				return nil, err
+			}
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return checkpoints, nil
+}
+
+// fetchCheckpoint backfills the checkpoint seq missing from a page.
+func (s *suiCheckpointStreamImpl) fetchCheckpoint(ctx context.Context, seq uint64) (models.CheckpointResponse, error) {
+	id := strconv.FormatUint(seq, 10)
+	checkpoint, err := s.system.SuiGetCheckpoint(ctx, models.SuiGetCheckpointRequest{CheckpointID: id})
+	if err != nil {
+		return checkpoint, err
+	}
+	if checkpoint.SequenceNumber != id {
+		return checkpoint, fmt.Errorf("%w: %d", ErrCheckpointMissing, seq)
+	}
+	return checkpoint, nil
+}
+
+// fetchTransactions fetches the transactions of digests in order, in chunks of multiGetTransactionBlocksLimit.
+func (s *suiCheckpointStreamImpl) fetchTransactions(ctx context.Context, digests []string, options models.SuiTransactionBlockOptions) ([]*models.SuiTransactionBlockResponse, error) {
+	transactions := make([]*models.SuiTransactionBlockResponse, 0, len(digests))
+	for start := 0; start < len(digests); start += multiGetTransactionBlocksLimit {
+		chunk := digests[start:min(start+multiGetTransactionBlocksLimit, len(digests))]
+		rsp, err := s.transaction.SuiMultiGetTransactionBlocks(ctx, models.SuiMultiGetTransactionBlocksRequest{
+			Digests: chunk,
+			Options: options,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(rsp) != len(chunk) {
+			return nil, fmt.Errorf("sui_multiGetTransactionBlocks returned %d transactions for %d digests", len(rsp), len(chunk))
+		}
+		transactions = append(transactions, rsp...)
+	}
+	return transactions, nil
+}
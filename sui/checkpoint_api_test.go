@@ -0,0 +1,173 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+// checkpointChain serves checkpoints up to a growing tip, `sui_getCheckpoints` leaves out the skipped ones.
+type checkpointChain struct {
+	tip     atomic.Uint64
+	skipped map[uint64]bool
+}
+
+func (c *checkpointChain) checkpoint(seq uint64) models.CheckpointResponse {
+	// checkpoint 5 holds more transactions than a single `sui_multiGetTransactionBlocks` call returns
+	n := 1
+	if seq == 5 {
+		n = 60
+	}
+	checkpoint := models.CheckpointResponse{SequenceNumber: strconv.FormatUint(seq, 10)}
+	for i := 0; i < n; i++ {
+		checkpoint.Transactions = append(checkpoint.Transactions, fmt.Sprintf("tx%d-%d", seq, i))
+	}
+	return checkpoint
+}
+
+func (c *checkpointChain) serve(srv *suitest.Server) {
+	srv.Handle("sui_getLatestCheckpointSequenceNumber", func(json.RawMessage) (interface{}, error) {
+		return strconv.FormatUint(c.tip.Load(), 10), nil
+	})
+	srv.Handle("sui_getCheckpoints", func(params json.RawMessage) (interface{}, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		var cursor *string
+		var limit uint64
+		_ = json.Unmarshal(args[0], &cursor)
+		_ = json.Unmarshal(args[1], &limit)
+		first := uint64(0)
+		if cursor != nil {
+			seq, _ := strconv.ParseUint(*cursor, 10, 64)
+			first = seq + 1
+		}
+		page := models.PaginatedCheckpointsResponse{Data: []models.CheckpointResponse{}}
+		for seq := first; seq < first+limit && seq <= c.tip.Load(); seq++ {
+			if !c.skipped[seq] {
+				page.Data = append(page.Data, c.checkpoint(seq))
+			}
+		}
+		return page, nil
+	})
+	srv.Handle("sui_getCheckpoint", func(params json.RawMessage) (interface{}, error) {
+		var args []string
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		seq, _ := strconv.ParseUint(args[0], 10, 64)
+		return c.checkpoint(seq), nil
+	})
+	srv.Handle("sui_multiGetTransactionBlocks", func(params json.RawMessage) (interface{}, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		var digests []string
+		_ = json.Unmarshal(args[0], &digests)
+		var rsp []models.SuiTransactionBlockResponse
+		for _, digest := range digests {
+			rsp = append(rsp, models.SuiTransactionBlockResponse{Digest: digest})
+		}
+		return rsp, nil
+	})
+}
+
+func TestStreamCheckpoints(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	chain := &checkpointChain{skipped: map[uint64]bool{7: true, 8: true}}
+	chain.tip.Store(12)
+	chain.serve(srv)
+	cli := NewSuiClient(srv.URL)
+
+	stop := errors.New("stop")
+	next := uint64(3)
+	err := cli.StreamCheckpoints(context.Background(), 3, func(checkpoint Checkpoint) error {
+		if checkpoint.SequenceNumber != strconv.FormatUint(next, 10) {
+			t.Fatalf("expected checkpoint %d, got %s", next, checkpoint.SequenceNumber)
+		}
+		if len(checkpoint.Transactions) != len(checkpoint.CheckpointResponse.Transactions) {
+			t.Fatalf("checkpoint %d: expected %d transactions, got %d", next, len(checkpoint.CheckpointResponse.Transactions), len(checkpoint.Transactions))
+		}
+		for i, tx := range checkpoint.Transactions {
+			if tx.Digest != checkpoint.CheckpointResponse.Transactions[i] {
+				t.Fatalf("checkpoint %d: transaction %d is %s", next, i, tx.Digest)
+			}
+		}
+		if next == 12 {
+			// the stream follows the tip
+			chain.tip.Store(20)
+		}
+		if next == 20 {
+			return stop
+		}
+		next++
+		return nil
+	}, WithCheckpointPageSize(3), WithCheckpointPollInterval(10*time.Millisecond),
+		WithCheckpointTransactions(models.SuiTransactionBlockOptions{ShowEffects: true}))
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+	if next != 20 {
+		t.Errorf("stopped at checkpoint %d", next)
+	}
+	if n := len(srv.CallsTo("sui_getCheckpoint")); n != 2 {
+		t.Errorf("expected the 2 checkpoints missing from the pages to be backfilled, got %d calls", n)
+	}
+
+	// the context ends the stream
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = cli.StreamCheckpoints(ctx, 21, func(Checkpoint) error { return nil }, WithCheckpointPollInterval(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestStreamCheckpointsTipPollRetry(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	chain := &checkpointChain{}
+	chain.tip.Store(2)
+	chain.serve(srv)
+	cli := NewSuiClient(srv.URL, WithRetryPolicy(httpconn.NoRetry()))
+
+	var delivered []string
+	err := cli.StreamCheckpoints(context.Background(), 0, func(checkpoint Checkpoint) error {
+		delivered = append(delivered, checkpoint.SequenceNumber)
+		switch checkpoint.SequenceNumber {
+		case "2":
+			// the polls fail transiently once caught up, the stream keeps following the tip
+			srv.FailHTTP("sui_getLatestCheckpointSequenceNumber", http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusServiceUnavailable)
+			chain.tip.Store(3)
+		case "3":
+			// a non-transient failure ends the stream
+			srv.SetError("sui_getLatestCheckpointSequenceNumber", -32603, "internal error")
+		}
+		return nil
+	}, WithCheckpointPollInterval(time.Millisecond))
+
+	var rpcErr *models.JsonRPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32603 {
+		t.Fatalf("expected the error of the poll, got %v", err)
+	}
+	if !slices.Equal(delivered, []string{"0", "1", "2", "3"}) {
+		t.Errorf("expected the checkpoints 0 to 3, got %v", delivered)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/transaction"
+)
+
+var ErrDryRunFailed = errors.New("transaction dry run failed")
+
+// minDryRunBudget is the lowest budget a transaction is built with for its dry run, 0.001 SUI.
+const minDryRunBudget = 1_000_000
+
+// GasEstimateConfig configures the gas budget estimation of a GasEstimator.
+type GasEstimateConfig struct {
+	// Margin is the fraction of the cost of the dry run added to the budget, 0.2 if 0. A negative margin adds none.
+	Margin float64
+	// OverheadUnits is the number of gas units added to the budget at the reference gas price, 1000 if 0.
+	OverheadUnits uint64
+	// DryRunBudget is the gas budget of the dry run, it must cover the execution of the transaction and be covered
+	// by its gas coins, 1 SUI if 0. The dry run falls back to a budget 10 times lower, down to 0.001 SUI, while the
+	// node rejects it, e.g. above the balance of the gas coin.
+	DryRunBudget uint64
+}
+
+func (c GasEstimateConfig) withDefaults() GasEstimateConfig {
+	if c.Margin == 0 {
+		c.Margin = 0.2
+	} else if c.Margin < 0 {
+		c.Margin = 0
+	}
+	if c.OverheadUnits == 0 {
+		c.OverheadUnits = 1000
+	}
+	if c.DryRunBudget == 0 {
+		c.DryRunBudget = 1_000_000_000
+	}
+	return c
+}
+
+// GasEstimator estimates the gas budget of transactions from a dry run: the computation cost plus the storage cost
+// net of the rebate, increased by the margin, plus the overhead at the reference gas price.
+type GasEstimator struct {
+	transaction IReadTransactionFromSuiAPI
+	system      IReadSystemFromSuiAPI
+	config      GasEstimateConfig
+}
+
+var _ transaction.GasEstimator = (*GasEstimator)(nil)
+
+// NewGasEstimator returns a GasEstimator running its dry runs on api, to pass to transaction.Builder.SetGasEstimator.
+func NewGasEstimator(api ISuiAPI, cfg GasEstimateConfig) *GasEstimator {
+	return &GasEstimator{transaction: api, system: api, config: cfg.withDefaults()}
+}
+
+// ReferenceGasPrice implements transaction.GasEstimator with `suix_getReferenceGasPrice`.
+func (e *GasEstimator) ReferenceGasPrice(ctx context.Context) (uint64, error) {
+	return e.system.SuiXGetReferenceGasPrice(ctx)
+}
+
+// EstimateGasBudget implements transaction.GasEstimator, data is dry run with the DryRunBudget, or a lower budget
+// while the node rejects it, as the gas payment must cover the budget.
+func (e *GasEstimator) EstimateGasBudget(ctx context.Context, data *transaction.TransactionData) (uint64, error) {
+	if data.V1 == nil {
+		return 0, transaction.ErrNotProgrammable
+	}
+	v1 := *data.V1
+	budget := e.config.DryRunBudget
+	for {
+		v1.GasData.Budget = budget
+		txBytes, err := (&transaction.TransactionData{V1: &v1}).TxBytes()
+		if err != nil {
+			return 0, err
+		}
+		gasUsed, err := e.dryRun(ctx, txBytes)
+		if err == nil {
+			return e.estimate(ctx, gasUsed)
+		}
+		var ok bool
+		if budget, ok = lowerDryRunBudget(budget, err); !ok {
+			return 0, err
+		}
+	}
+}
+
+// EstimateTxBytes returns the gas budget of the base64 transaction data txBytes, dry run as they are.
+func (e *GasEstimator) EstimateTxBytes(ctx context.Context, txBytes string) (uint64, error) {
+	gasUsed, err := e.dryRun(ctx, txBytes)
+	if err != nil {
+		return 0, err
+	}
+	return e.estimate(ctx, gasUsed)
+}
+
+func (e *GasEstimator) dryRun(ctx context.Context, txBytes string) (models.GasCostSummary, error) {
+	rsp, err := e.transaction.SuiDryRunTransactionBlock(ctx, models.SuiDryRunTransactionBlockRequest{TxBytes: txBytes})
+	if err != nil {
+		return models.GasCostSummary{}, err
+	}
+	if status := rsp.Effects.Status; status.Status != "success" {
+		return models.GasCostSummary{}, fmt.Errorf("%w: %s", ErrDryRunFailed, status.Error)
+	}
+	return rsp.Effects.GasUsed, nil
+}
+
+func (e *GasEstimator) estimate(ctx context.Context, gasUsed models.GasCostSummary) (uint64, error) {
+	price, err := e.ReferenceGasPrice(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return e.budget(gasUsed, price)
+}
+
+// lowerDryRunBudget returns the budget to dry run with once the node rejected budget with err, 10 times lower down
+// to minDryRunBudget. It returns false if err isn't a rejection by the node or no lower budget is left.
+func lowerDryRunBudget(budget uint64, err error) (uint64, bool) {
+	var rpcErr *models.JsonRPCError
+	if !errors.As(err, &rpcErr) || budget/10 < minDryRunBudget {
+		return 0, false
+	}
+	return budget / 10, true
+}
+
+func (e *GasEstimator) budget(gasUsed models.GasCostSummary, price uint64) (uint64, error) {
+	var costs [3]uint64
+	for i, cost := range []string{gasUsed.ComputationCost, gasUsed.StorageCost, gasUsed.StorageRebate} {
+		n, err := strconv.ParseUint(cost, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid gas cost summary %+v: %w", gasUsed, err)
+		}
+		costs[i] = n
+	}
+	computation, storage, rebate := costs[0], costs[1], costs[2]
+	// the rebate lowers the cost, but the computation is paid before it is refunded
+	cost := computation
+	if storage > rebate {
+		cost += storage - rebate
+	}
+	return cost + uint64(float64(cost)*e.config.Margin) + e.config.OverheadUnits*price, nil
+}
+
+// buildTransaction calls the `unsafe_*` method op builds. When gasBudget is empty and the client was created
+// WithGasEstimation, the transaction is built with the DryRunBudget, dry run, and built again with its estimated budget.
+func (s *suiWriteTransactionImpl) buildTransaction(ctx context.Context, gasBudget string, op func(gasBudget string) httpconn.Operation) (models.TxnMetaData, error) {
+	var rsp models.TxnMetaData
+	if gasBudget == "" && s.gasEstimator != nil {
+		if err := s.buildDryRun(ctx, &rsp, op); err != nil {
+			return rsp, err
+		}
+		budget, err := s.gasEstimator.EstimateTxBytes(ctx, rsp.TxBytes)
+		if err != nil {
+			return rsp, fmt.Errorf("estimate gas budget: %w", err)
+		}
+		gasBudget = strconv.FormatUint(budget, 10)
+		rsp = models.TxnMetaData{}
+	}
+	err := s.conn.CallContext(ctx, &rsp, op(gasBudget))
+	return rsp, err
+}
+
+// buildDryRun builds the transaction of op with the DryRunBudget, or a lower budget while the node rejects it, as
+// the budget and the amount transferred must be covered by the gas coin.
+func (s *suiWriteTransactionImpl) buildDryRun(ctx context.Context, rsp *models.TxnMetaData, op func(gasBudget string) httpconn.Operation) error {
+	budget := s.gasEstimator.config.DryRunBudget
+	for {
+		err := s.conn.CallContext(ctx, rsp, op(strconv.FormatUint(budget, 10)))
+		if err == nil {
+			return nil
+		}
+		var ok bool
+		if budget, ok = lowerDryRunBudget(budget, err); !ok {
+			return err
+		}
+	}
+}
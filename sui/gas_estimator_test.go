@@ -0,0 +1,150 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+	"github.com/yasir7ca/sui-go-sdk/transaction"
+)
+
+// the default dry run costs 750000 + 1976000 - 978120 MIST, +20% and 1000 units at 750 MIST
+const estimatedBudget = 1747880 + 349576 + 750000
+
+func gasBudgetParam(t *testing.T, call suitest.Call, i int) string {
+	var params []json.RawMessage
+	var budget string
+	if err := json.Unmarshal(call.Params, &params); err != nil || json.Unmarshal(params[i], &budget) != nil {
+		t.Fatalf("unexpected params %s", call.Params)
+	}
+	return budget
+}
+
+func TestGasEstimation(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	cli := NewSuiClient(srv.URL, WithGasEstimation(GasEstimateConfig{}))
+	req := models.TransferSuiRequest{Signer: suitest.Address, SuiObjectId: suitest.GasCoinObjectID, Recipient: suitest.Address, Amount: "1"}
+
+	if _, err := cli.TransferSui(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	calls := srv.CallsTo("unsafe_transferSui")
+	if len(calls) != 2 || gasBudgetParam(t, calls[0], 2) != "1000000000" || gasBudgetParam(t, calls[1], 2) != strconv.FormatUint(estimatedBudget, 10) {
+		t.Fatalf("expected the transaction to be built with the dry run budget, then the estimated one, got %v", calls)
+	}
+
+	// a budget set on the request is kept
+	req.GasBudget = "5000000"
+	if _, err := cli.TransferSui(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if calls := srv.CallsTo("unsafe_transferSui"); len(calls) != 3 || gasBudgetParam(t, calls[2], 2) != "5000000" {
+		t.Errorf("expected a single call with the budget of the request, got %v", calls)
+	}
+	if n := len(srv.CallsTo("sui_dryRunTransactionBlock")); n != 1 {
+		t.Errorf("expected a single dry run, got %d", n)
+	}
+
+	// a dry run budget above the balance of the gas coin is lowered until the node accepts it
+	srv.Handle("unsafe_paySui", func(params json.RawMessage) (interface{}, error) {
+		var args []json.RawMessage
+		var budget string
+		if err := json.Unmarshal(params, &args); err != nil || json.Unmarshal(args[4], &budget) != nil {
+			return nil, err
+		}
+		if n, _ := strconv.ParseUint(budget, 10, 64); n > 20_000_000 {
+			return nil, &models.JsonRPCError{Code: -32602, Message: "Balance of gas object is lower than the needed amount"}
+		}
+		return models.TxnMetaData{TxBytes: "AAACAAgA6HZIFwAAAAAg"}, nil
+	})
+	if _, err := cli.PaySui(ctx, models.PaySuiRequest{Signer: suitest.Address}); err != nil {
+		t.Fatal(err)
+	}
+	var budgets []string
+	for _, call := range srv.CallsTo("unsafe_paySui") {
+		budgets = append(budgets, gasBudgetParam(t, call, 4))
+	}
+	if expected := []string{"1000000000", "100000000", "10000000", strconv.FormatUint(estimatedBudget, 10)}; !slices.Equal(budgets, expected) {
+		t.Errorf("expected the budgets %v, got %v", expected, budgets)
+	}
+
+	srv.SetResult("sui_dryRunTransactionBlock", map[string]interface{}{
+		"effects": map[string]interface{}{"status": map[string]string{"status": "failure", "error": "InsufficientGas"}},
+	})
+	if _, err := cli.Pay(ctx, models.PayRequest{Signer: suitest.Address}); !errors.Is(err, ErrDryRunFailed) {
+		t.Errorf("expected ErrDryRunFailed, got %v", err)
+	}
+}
+
+func TestBuilderGasEstimation(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	cli := NewSuiClient(srv.URL)
+
+	gas, _ := bcs.ParseObjectRef(suitest.GasCoinObjectID, 41, suitest.ObjectDigest)
+	b := transaction.NewBuilder().
+		SetSender(bcs.MustParseSuiAddress(suitest.Address)).
+		SetGasPayment(gas).
+		SetGasEstimator(NewGasEstimator(cli, GasEstimateConfig{DryRunBudget: 50000000}))
+	b.TransferObjects(b.SplitCoins(b.Gas(), b.Pure(uint64(1))), b.PureAddress(suitest.Address))
+	data, err := b.BuildContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.V1.GasData.Price != 750 || data.V1.GasData.Budget != estimatedBudget {
+		t.Errorf("unexpected gas data %+v", data.V1.GasData)
+	}
+
+	var params []string
+	dryRuns := srv.CallsTo("sui_dryRunTransactionBlock")
+	if len(dryRuns) != 1 || json.Unmarshal(dryRuns[0].Params, &params) != nil {
+		t.Fatalf("unexpected dry runs %v", dryRuns)
+	}
+	dryRun, err := transaction.DecodeTxBytes(params[0])
+	if err != nil || dryRun.V1.GasData.Budget != 50000000 {
+		t.Errorf("expected the dry run to use the dry run budget, got %+v, %v", dryRun, err)
+	}
+
+	// a dry run budget above the balance of the gas coin is lowered until the node accepts it
+	var budgets []uint64
+	srv.Handle("sui_dryRunTransactionBlock", func(params json.RawMessage) (interface{}, error) {
+		var args []string
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		data, err := transaction.DecodeTxBytes(args[0])
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, data.V1.GasData.Budget)
+		if data.V1.GasData.Budget > 20_000_000 {
+			return nil, &models.JsonRPCError{Code: -32602, Message: "Balance of gas object is lower than the needed amount"}
+		}
+		return map[string]interface{}{"effects": map[string]interface{}{
+			"status":  map[string]string{"status": "success"},
+			"gasUsed": models.GasCostSummary{ComputationCost: "750000", StorageCost: "1976000", StorageRebate: "978120"},
+		}}, nil
+	})
+	b = transaction.NewBuilder().
+		SetSender(bcs.MustParseSuiAddress(suitest.Address)).
+		SetGasPayment(gas).
+		SetGasEstimator(NewGasEstimator(cli, GasEstimateConfig{}))
+	b.TransferObjects(b.SplitCoins(b.Gas(), b.Pure(uint64(1))), b.PureAddress(suitest.Address))
+	if data, err = b.BuildContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if data.V1.GasData.Budget != estimatedBudget || !slices.Equal(budgets, []uint64{1_000_000_000, 100_000_000, 10_000_000}) {
+		t.Errorf("expected the dry run budget to be lowered, got the budgets %v and %+v", budgets, data.V1.GasData)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/transaction"
+)
+
+var ErrInsufficientGasCoins = errors.New("insufficient gas coins")
+
+// MaxGasPaymentObjects is the maximum number of coins paying for the gas of a transaction, the
+// `max_gas_payment_objects` of the protocol config.
+const MaxGasPaymentObjects = 256
+
+// GasCoin is a SUI coin that can pay for gas.
+type GasCoin struct {
+	Ref     bcs.ObjectRef
+	Balance uint64
+}
+
+// CoinSelectionStrategy picks at most max of coins whose balances add up to budget, it returns nil if it can't.
+// It must not modify coins.
+type CoinSelectionStrategy func(coins []GasCoin, budget uint64, max int) []GasCoin
+
+// SmallestSufficient picks the smallest coin covering the budget. When none does, it picks the smallest coins
+// covering it, merging them into the gas coin.
+func SmallestSufficient(coins []GasCoin, budget uint64, max int) []GasCoin {
+	sorted := sortCoins(coins, func(a, b GasCoin) int { return cmp.Compare(a.Balance, b.Balance) })
+	i, _ := slices.BinarySearchFunc(sorted, budget, func(c GasCoin, budget uint64) int { return cmp.Compare(c.Balance, budget) })
+	if i < len(sorted) {
+		return sorted[i : i+1]
+	}
+	// the window of the max smallest coins slides up until it covers the budget
+	var total uint64
+	start := 0
+	for end, c := range sorted {
+		total += c.Balance
+		if end-start == max {
+			total -= sorted[start].Balance
+			start++
+		}
+		if total >= budget {
+			return sorted[start : end+1]
+		}
+	}
+	return nil
+}
+
+// LargestFirst picks the largest coins until they cover the budget.
+func LargestFirst(coins []GasCoin, budget uint64, max int) []GasCoin {
+	sorted := sortCoins(coins, func(a, b GasCoin) int { return cmp.Compare(b.Balance, a.Balance) })
+	var total uint64
+	for i, c := range sorted[:min(max, len(sorted))] {
+		if total += c.Balance; total >= budget {
+			return sorted[:i+1]
+		}
+	}
+	return nil
+}
+
+// FewestCoins picks as few coins as LargestFirst, but the last one is the smallest coin completing the budget
+// rather than the next largest.
+func FewestCoins(coins []GasCoin, budget uint64, max int) []GasCoin {
+	sorted := sortCoins(coins, func(a, b GasCoin) int { return cmp.Compare(b.Balance, a.Balance) })
+	var total uint64
+	for n, c := range sorted[:min(max, len(sorted))] {
+		if total+c.Balance < budget {
+			total += c.Balance
+			continue
+		}
+		// the coins after the n largest are sorted down, the last one covering the rest is the smallest
+		last := n
+		for last+1 < len(sorted) && total+sorted[last+1].Balance >= budget {
+			last++
+		}
+		return append(sorted[:n:n], sorted[last])
+	}
+	return nil
+}
+
+func sortCoins(coins []GasCoin, compare func(a, b GasCoin) int) []GasCoin {
+	sorted := slices.Clone(coins)
+	slices.SortStableFunc(sorted, compare)
+	return sorted
+}
+
+// GasSelectConfig configures the gas coin selection of a GasCoinSelector.
+type GasSelectConfig struct {
+	// Strategy picks the coins among the SUI coins of the owner, SmallestSufficient if nil.
+	Strategy CoinSelectionStrategy
+	// MaxCoins is the maximum number of coins picked, MaxGasPaymentObjects if 0 or above.
+	MaxCoins int
+}
+
+func (c GasSelectConfig) withDefaults() GasSelectConfig {
+	if c.Strategy == nil {
+		c.Strategy = SmallestSufficient
+	}
+	if c.MaxCoins <= 0 || c.MaxCoins > MaxGasPaymentObjects {
+		c.MaxCoins = MaxGasPaymentObjects
+	}
+	return c
+}
+
+// GasCoinSelector picks the SUI coins of an address paying for the gas of a transaction.
+type GasCoinSelector struct {
+	coin   IReadCoinFromSuiAPI
+	config GasSelectConfig
+}
+
+var _ transaction.GasSelector = (*GasCoinSelector)(nil)
+
+// NewGasCoinSelector returns a GasCoinSelector listing coins with `suix_getCoins` on api, to pass to
+// transaction.Builder.SetGasSelector.
+func NewGasCoinSelector(api IReadCoinFromSuiAPI, cfg GasSelectConfig) *GasCoinSelector {
+	return &GasCoinSelector{coin: api, config: cfg.withDefaults()}
+}
+
+// SelectGas implements transaction.GasSelector, it returns the coins of owner picked by the Strategy to cover
+// budget, none of them in exclude. The error wraps ErrInsufficientGasCoins when they can't cover it.
+func (s *GasCoinSelector) SelectGas(ctx context.Context, owner bcs.SuiAddress, budget uint64, exclude []bcs.ObjectID) ([]bcs.ObjectRef, error) {
+	coins, err := s.Coins(ctx, owner, exclude)
+	if err != nil {
+		return nil, err
+	}
+	var total uint64
+	for _, c := range coins {
+		total += c.Balance
+	}
+	if total < budget {
+		return nil, fmt.Errorf("%w: balance %d below budget %d", ErrInsufficientGasCoins, total, budget)
+	}
+	picked := s.config.Strategy(coins, budget, s.config.MaxCoins)
+	if picked == nil {
+		return nil, fmt.Errorf("%w: budget %d not covered by %d coins", ErrInsufficientGasCoins, budget, s.config.MaxCoins)
+	}
+	refs := make([]bcs.ObjectRef, len(picked))
+	for i, c := range picked {
+		refs[i] = c.Ref
+	}
+	return refs, nil
+}
+
+// Coins returns the SUI coins of owner, except the ones in exclude.
+func (s *GasCoinSelector) Coins(ctx context.Context, owner bcs.SuiAddress, exclude []bcs.ObjectID) ([]GasCoin, error) {
+	var coins []GasCoin
+	req := models.SuiXGetCoinsRequest{Owner: owner.String(), CoinType: "0x2::sui::SUI"}
+	for c, err := range IterCoins(s.coin, req).All(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		coin, err := gasCoin(c)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(exclude, coin.Ref.ObjectID) {
+			coins = append(coins, coin)
+		}
+	}
+	return coins, nil
+}
+
+func gasCoin(c models.CoinData) (GasCoin, error) {
+	version, err := strconv.ParseUint(c.Version, 10, 64)
+	if err != nil {
+		return GasCoin{}, fmt.Errorf("invalid version of coin %s: %w", c.CoinObjectId, err)
+	}
+	balance, err := strconv.ParseUint(c.Balance, 10, 64)
+	if err != nil {
+		return GasCoin{}, fmt.Errorf("invalid balance of coin %s: %w", c.CoinObjectId, err)
+	}
+	ref, err := bcs.ParseObjectRef(c.CoinObjectId, version, c.Digest)
+	if err != nil {
+		return GasCoin{}, err
+	}
+	return GasCoin{Ref: ref, Balance: balance}, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+	"github.com/yasir7ca/sui-go-sdk/transaction"
+)
+
+// gasCoins returns coins 0x1, 0x2, ... of balances 5, 10, 20, 40 and 100 MIST.
+func gasCoins() []GasCoin {
+	var coins []GasCoin
+	for i, balance := range []uint64{5, 10, 20, 40, 100} {
+		coins = append(coins, GasCoin{Ref: bcs.ObjectRef{ObjectID: bcs.MustParseSuiAddress(fmt.Sprintf("0x%d", i+1))}, Balance: balance})
+	}
+	return coins
+}
+
+func balances(coins []GasCoin) []uint64 {
+	var b []uint64
+	for _, c := range coins {
+		b = append(b, c.Balance)
+	}
+	return b
+}
+
+func TestCoinSelectionStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy CoinSelectionStrategy
+		budget   uint64
+		max      int
+		want     []uint64
+	}{
+		{"smallest sufficient", SmallestSufficient, 15, 256, []uint64{20}},
+		{"smallest sufficient merging coins", SmallestSufficient, 120, 256, []uint64{5, 10, 20, 40, 100}},
+		{"smallest sufficient at most 2 coins", SmallestSufficient, 120, 2, []uint64{40, 100}},
+		{"largest first", LargestFirst, 120, 256, []uint64{100, 40}},
+		{"largest first at most 1 coin", LargestFirst, 120, 1, nil},
+		{"fewest coins", FewestCoins, 120, 256, []uint64{100, 20}},
+		{"fewest coins in one", FewestCoins, 15, 256, []uint64{20}},
+		{"not covered", FewestCoins, 200, 256, nil},
+	}
+	for _, tt := range tests {
+		coins := gasCoins()
+		if got := balances(tt.strategy(coins, tt.budget, tt.max)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		if !reflect.DeepEqual(coins, gasCoins()) {
+			t.Errorf("%s: coins were modified", tt.name)
+		}
+	}
+}
+
+func TestBuilderGasSelection(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	page := models.PaginatedCoinsResponse{}
+	for _, c := range gasCoins() {
+		page.Data = append(page.Data, models.CoinData{
+			CoinType:     "0x2::sui::SUI",
+			CoinObjectId: c.Ref.ObjectID.String(),
+			Version:      "1",
+			Digest:       suitest.ObjectDigest,
+			Balance:      fmt.Sprint(c.Balance),
+		})
+	}
+	srv.SetResult("suix_getCoins", page)
+	selector := NewGasCoinSelector(NewSuiClient(srv.URL), GasSelectConfig{Strategy: FewestCoins})
+
+	build := func(budget uint64) (models.TxnMetaData, error) {
+		b := transaction.NewBuilder().
+			SetSender(bcs.MustParseSuiAddress(suitest.Address)).
+			SetGasPrice(1000).
+			SetGasBudget(budget).
+			SetGasSelector(selector)
+		// the coin of 100 MIST is an input, it can't pay for the gas
+		coin, _ := bcs.ParseObjectRef("0x5", 1, suitest.ObjectDigest)
+		b.TransferObjects([]transaction.Argument{b.OwnedObject(coin)}, b.PureAddress(suitest.Address))
+		return b.BuildTxnMetaData()
+	}
+	meta, err := build(60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Gas) != 2 || meta.Gas[0].ObjectId != page.Data[3].CoinObjectId || meta.Gas[1].ObjectId != page.Data[2].CoinObjectId {
+		t.Errorf("expected the coins of 40 and 20 MIST, got %+v", meta.Gas)
+	}
+	data, _ := transaction.DecodeTxBytes(meta.TxBytes)
+	if payment := data.V1.GasData.Payment; len(payment) != 2 || payment[0].ObjectID != bcs.MustParseSuiAddress("0x4") {
+		t.Errorf("unexpected gas payment %+v", payment)
+	}
+	if _, err := build(100); !errors.Is(err, ErrInsufficientGasCoins) {
+		t.Errorf("expected ErrInsufficientGasCoins, got %v", err)
+	}
+
+	// the gas coin covers the budget and the amount split off it
+	b := transaction.NewBuilder().
+		SetSender(bcs.MustParseSuiAddress(suitest.Address)).
+		SetGasPrice(1000).
+		SetGasBudget(15).
+		SetGasSelector(NewGasCoinSelector(NewSuiClient(srv.URL), GasSelectConfig{Strategy: SmallestSufficient}))
+	b.TransferObjects(b.SplitCoins(b.Gas(), b.Pure(uint64(30))), b.PureAddress(suitest.Address))
+	if meta, err = b.BuildTxnMetaData(); err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Gas) != 1 || meta.Gas[0].ObjectId != page.Data[4].CoinObjectId {
+		t.Errorf("expected the coin of 100 MIST, got %+v", meta.Gas)
+	}
+}
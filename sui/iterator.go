@@ -0,0 +1,250 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+var (
+	ErrTooManyItems       = errors.New("iterator yielded more items than the limit")
+	ErrCursorNotAdvancing = errors.New("paginated method returned an empty page and the same cursor")
+)
+
+// DefaultCollectLimit is the number of items CollectAll collects at most when no limit is given.
+const DefaultCollectLimit = 10000
+
+// maxPageSize is the largest page served by the paginated methods of the node.
+const maxPageSize = 50
+
+// PageCursor is the position of an Iterator: the cursor of the page holding the next item and the number of items
+// of that page already yielded. Passed to Iterator.From, it resumes the iteration after the last item yielded.
+type PageCursor[C any] struct {
+	// Page is the cursor of the page holding the next item, nil for the first page.
+	Page *C
+	// Offset is the number of items of that page already yielded.
+	Offset int
+}
+
+// PageOption configures an Iterator.
+type PageOption func(*pageOptions)
+
+type pageOptions struct {
+	pageSize uint64
+	maxItems int
+}
+
+// WithPageSize is the number of items fetched per call, up to 50, larger sizes fetch 50. It overrides the Limit of
+// the request.
+func WithPageSize(size uint64) PageOption {
+	return func(o *pageOptions) {
+		o.pageSize = size
+	}
+}
+
+// WithMaxItems stops the iteration after n items, 0 means no limit.
+func WithMaxItems(n int) PageOption {
+	return func(o *pageOptions) {
+		o.maxItems = n
+	}
+}
+
+// Iterator iterates over the items of a paginated method, fetching the pages as they are needed. C is the type
+// of the cursor of the method, models.EventId for the events and the string of the others.
+type Iterator[T any, C comparable] struct {
+	fetch    func(ctx context.Context, cursor *C, limit uint64) ([]T, C, bool, error)
+	pageSize uint64
+	maxItems int
+	cursor   PageCursor[C]
+	yielded  int
+}
+
+func newIterator[T any, C comparable](start interface{}, limit uint64, opts []PageOption, fetch func(ctx context.Context, cursor *C, limit uint64) ([]T, C, bool, error)) *Iterator[T, C] {
+	options := pageOptions{pageSize: limit}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.pageSize > maxPageSize {
+		options.pageSize = maxPageSize
+	}
+	return &Iterator[T, C]{
+		fetch:    fetch,
+		pageSize: options.pageSize,
+		maxItems: options.maxItems,
+		cursor:   PageCursor[C]{Page: startCursor[C](start)},
+	}
+}
+
+// startCursor returns the cursor of a request, nil if it isn't set or isn't a C.
+func startCursor[C any](cursor interface{}) *C {
+	switch c := cursor.(type) {
+	case C:
+		return &c
+	case *C:
+		return c
+	default:
+		return nil
+	}
+}
+
+// From moves the iterator to cursor, as returned by Cursor.
+func (it *Iterator[T, C]) From(cursor PageCursor[C]) *Iterator[T, C] {
+	it.cursor = cursor
+	return it
+}
+
+// Cursor returns the position of the iterator, after the last item yielded.
+func (it *Iterator[T, C]) Cursor() PageCursor[C] {
+	return it.cursor
+}
+
+// All returns the items from the position of the iterator on, and the error ending the iteration if any. Once
+// the last page is reached the iteration stops, ranging over All again yields the items added since.
+func (it *Iterator[T, C]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			if it.maxItems > 0 && it.yielded >= it.maxItems {
+				return
+			}
+			items, next, hasNext, err := it.fetch(ctx, it.cursor.Page, it.pageSize)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for it.cursor.Offset < len(items) {
+				if it.maxItems > 0 && it.yielded >= it.maxItems {
+					return
+				}
+				item := items[it.cursor.Offset]
+				it.cursor.Offset++
+				it.yielded++
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !hasNext {
+				return
+			}
+			if len(items) == 0 && it.cursor.Page != nil && next == *it.cursor.Page {
+				var zero T
+				yield(zero, ErrCursorNotAdvancing)
+				return
+			}
+			it.cursor = PageCursor[C]{Page: &next}
+		}
+	}
+}
+
+// CollectAll collects the items of seq, up to limit items or DefaultCollectLimit if limit is 0. It returns the
+// items collected and ErrTooManyItems if seq holds more, or the error ending seq.
+func CollectAll[T any](seq iter.Seq2[T, error], limit int) ([]T, error) {
+	if limit <= 0 {
+		limit = DefaultCollectLimit
+	}
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		if len(items) == limit {
+			return items, ErrTooManyItems
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// cursorParam returns the cursor of the request of a page, nil for the first page.
+func cursorParam[C any](cursor *C) interface{} {
+	if cursor == nil {
+		return nil
+	}
+	return *cursor
+}
+
+// IterCoins iterates over `suix_getCoins`, from the Cursor of req.
+func IterCoins(api IReadCoinFromSuiAPI, req models.SuiXGetCoinsRequest, opts ...PageOption) *Iterator[models.CoinData, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]models.CoinData, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXGetCoins(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterAllCoins iterates over `suix_getAllCoins`, from the Cursor of req.
+func IterAllCoins(api IReadCoinFromSuiAPI, req models.SuiXGetAllCoinsRequest, opts ...PageOption) *Iterator[models.CoinData, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]models.CoinData, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXGetAllCoins(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterOwnedObjects iterates over `suix_getOwnedObjects`, from the Cursor of req.
+func IterOwnedObjects(api IReadObjectFromSuiAPI, req models.SuiXGetOwnedObjectsRequest, opts ...PageOption) *Iterator[models.SuiObjectResponse, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]models.SuiObjectResponse, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXGetOwnedObjects(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterDynamicFields iterates over `suix_getDynamicFields`, from the Cursor of req.
+func IterDynamicFields(api IReadObjectFromSuiAPI, req models.SuiXGetDynamicFieldRequest, opts ...PageOption) *Iterator[models.DynamicFieldInfo, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]models.DynamicFieldInfo, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXGetDynamicField(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterEvents iterates over `suix_queryEvents`, from the Cursor of req.
+func IterEvents(api IReadEventFromSuiAPI, req models.SuiXQueryEventsRequest, opts ...PageOption) *Iterator[models.SuiEventResponse, models.EventId] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *models.EventId, limit uint64) ([]models.SuiEventResponse, models.EventId, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXQueryEvents(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterTransactionBlocks iterates over `suix_queryTransactionBlocks`, from the Cursor of req.
+func IterTransactionBlocks(api IReadTransactionFromSuiAPI, req models.SuiXQueryTransactionBlocksRequest, opts ...PageOption) *Iterator[models.SuiTransactionBlockResponse, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]models.SuiTransactionBlockResponse, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXQueryTransactionBlocks(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterCheckpoints iterates over `sui_getCheckpoints`, from the Cursor of req.
+func IterCheckpoints(api IReadSystemFromSuiAPI, req models.SuiGetCheckpointsRequest, opts ...PageOption) *Iterator[models.CheckpointResponse, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]models.CheckpointResponse, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiGetCheckpoints(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterEpochs iterates over `suix_getEpochs`, from the Cursor of req.
+func IterEpochs(api IReadSystemFromSuiAPI, req models.SuiXGetEpochsRequest, opts ...PageOption) *Iterator[models.EpochInfo, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]models.EpochInfo, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXGetEpochs(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
+
+// IterNameServiceNames iterates over `suix_resolveNameServiceNames`, from the Cursor of req.
+func IterNameServiceNames(api IReadNameServiceFromSuiAPI, req models.SuiXResolveNameServiceNamesRequest, opts ...PageOption) *Iterator[string, string] {
+	return newIterator(req.Cursor, req.Limit, opts, func(ctx context.Context, cursor *string, limit uint64) ([]string, string, bool, error) {
+		req.Cursor, req.Limit = cursorParam(cursor), limit
+		rsp, err := api.SuiXResolveNameServiceNames(ctx, req)
+		return rsp.Data, rsp.NextCursor, rsp.HasNextPage, err
+	})
+}
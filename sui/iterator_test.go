@@ -0,0 +1,122 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+// coinPages serves `suix_getAllCoins` pages of n coins, their cursor is the id of the last coin of the page.
+func coinPages(n int) suitest.Handler {
+	return func(params json.RawMessage) (interface{}, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		var cursor *string
+		var limit int
+		_ = json.Unmarshal(args[1], &cursor)
+		_ = json.Unmarshal(args[2], &limit)
+		if limit == 0 {
+			limit = 50
+		}
+		start := 0
+		if cursor != nil {
+			_, _ = fmt.Sscanf(*cursor, "0x%d", &start)
+			start++
+		}
+		page := models.PaginatedCoinsResponse{Data: []models.CoinData{}}
+		for i := start; i < min(start+limit, n); i++ {
+			page.Data = append(page.Data, models.CoinData{CoinObjectId: fmt.Sprintf("0x%d", i)})
+		}
+		page.HasNextPage = start+limit < n
+		if len(page.Data) > 0 {
+			page.NextCursor = page.Data[len(page.Data)-1].CoinObjectId
+		}
+		return page, nil
+	}
+}
+
+func coinIDs(coins []models.CoinData) []string {
+	ids := make([]string, len(coins))
+	for i, coin := range coins {
+		ids[i] = coin.CoinObjectId
+	}
+	return ids
+}
+
+func TestIterator(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	srv.Handle("suix_getAllCoins", coinPages(7))
+	cli := NewSuiClient(srv.URL)
+	ctx := context.Background()
+	req := models.SuiXGetAllCoinsRequest{Owner: suitest.Address}
+
+	coins, err := CollectAll(IterAllCoins(cli, req, WithPageSize(3)).All(ctx), 0)
+	if err != nil || len(coins) != 7 {
+		t.Fatalf("unexpected coins %v, %v", coinIDs(coins), err)
+	}
+	if n := len(srv.CallsTo("suix_getAllCoins")); n != 3 {
+		t.Errorf("expected 3 pages, got %d", n)
+	}
+
+	// stopping mid-page and resuming from the cursor yields every coin once
+	it := IterAllCoins(cli, req, WithPageSize(3), WithMaxItems(4))
+	first, err := CollectAll(it.All(ctx), 0)
+	if err != nil || len(first) != 4 {
+		t.Fatalf("unexpected coins %v, %v", coinIDs(first), err)
+	}
+	cursor := it.Cursor()
+	if cursor.Page == nil || *cursor.Page != "0x2" || cursor.Offset != 1 {
+		t.Errorf("unexpected cursor %+v", cursor)
+	}
+	rest, err := CollectAll(IterAllCoins(cli, req, WithPageSize(3)).From(cursor).All(ctx), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(coinIDs(append(first, rest...))); got != fmt.Sprint(coinIDs(coins)) {
+		t.Errorf("resumed iteration yielded %s", got)
+	}
+
+	// the cursor of the request is the start of the iteration
+	rest, err = CollectAll(IterAllCoins(cli, models.SuiXGetAllCoinsRequest{Owner: suitest.Address, Cursor: "0x4"}).All(ctx), 0)
+	if err != nil || fmt.Sprint(coinIDs(rest)) != "[0x5 0x6]" {
+		t.Errorf("unexpected coins %v, %v", coinIDs(rest), err)
+	}
+
+	// the page size is capped to the 50 items a node serves
+	srv.Handle("suix_getAllCoins", coinPages(120))
+	calls := len(srv.CallsTo("suix_getAllCoins"))
+	coins, err = CollectAll(IterAllCoins(cli, req, WithPageSize(1000)).All(ctx), 0)
+	if err != nil || len(coins) != 120 {
+		t.Fatalf("unexpected coins %v, %v", coinIDs(coins), err)
+	}
+	for _, call := range srv.CallsTo("suix_getAllCoins")[calls:] {
+		var args []json.RawMessage
+		if err := json.Unmarshal(call.Params, &args); err != nil || string(args[2]) != "50" {
+			t.Errorf("expected pages of 50 coins, got %s", call.Params)
+		}
+	}
+	srv.Handle("suix_getAllCoins", coinPages(7))
+
+	coins, err = CollectAll(IterAllCoins(cli, req).All(ctx), 5)
+	if !errors.Is(err, ErrTooManyItems) || len(coins) != 5 {
+		t.Errorf("expected 5 coins and ErrTooManyItems, got %d, %v", len(coins), err)
+	}
+
+	srv.SetError("suix_getAllCoins", -32602, "Invalid params")
+	for _, err := range IterAllCoins(cli, req).All(ctx) {
+		if err == nil {
+			t.Error("expected the error of the call")
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+// Option configures the Client created by NewSuiClient and the WebsocketClient created by NewSuiWebsocketClient.
+// Options that only make sense for one transport are ignored by the other.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	http httpconn.Config
+	ws   wsconn.Config
+	pool *httpconn.Pool
+
+	objectDataOptions       *models.SuiObjectDataOptions
+	transactionBlockOptions *models.SuiTransactionBlockOptions
+	subscriptionErrors      SubscriptionErrorHandler
+	poll                    PollConfig
+	gasEstimate             *GasEstimateConfig
+}
+
+func newClientOptions(opts []Option) *clientOptions {
+	o := &clientOptions{
+		http: httpconn.DefaultConfig(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// objectOptions returns the default SuiObjectDataOptions if none were set on the request.
+func (o *clientOptions) objectOptions(options models.SuiObjectDataOptions) models.SuiObjectDataOptions {
+	if o == nil || o.objectDataOptions == nil || options != (models.SuiObjectDataOptions{}) {
+		return options
+	}
+	return *o.objectDataOptions
+}
+
+// transactionOptions returns the default SuiTransactionBlockOptions if none were set on the request.
+func (o *clientOptions) transactionOptions(options models.SuiTransactionBlockOptions) models.SuiTransactionBlockOptions {
+	if o == nil || o.transactionBlockOptions == nil || options != (models.SuiTransactionBlockOptions{}) {
+		return options
+	}
+	return *o.transactionBlockOptions
+}
+
+// subscriptionErrorHandler returns the SubscriptionErrorHandler of the subscriptions, the default one logs and skips.
+func (o *clientOptions) subscriptionErrorHandler() SubscriptionErrorHandler {
+	if o.subscriptionErrors != nil {
+		return o.subscriptionErrors
+	}
+	logger := o.ws.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logSubscriptionError(logger)
+}
+
+// WithTimeout bounds a single HTTP request, or the websocket handshake.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.http.Timeout = timeout
+		o.ws.HandshakeTimeout = timeout
+	}
+}
+
+// WithHTTPClient sends requests with a custom HTTP client, the timeout and connection pool options are then ignored.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *clientOptions) {
+		o.http.Client = c
+	}
+}
+
+// WithConnectionPool sizes the connection pool of the HTTP transport.
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost, maxConnsPerHost int, idleConnTimeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.http.MaxIdleConns = maxIdleConns
+		o.http.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		o.http.MaxConnsPerHost = maxConnsPerHost
+		o.http.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// WithHeader adds a header to every HTTP request and to the websocket handshake.
+func WithHeader(key, value string) Option {
+	return func(o *clientOptions) {
+		if o.http.Header == nil {
+			o.http.Header = http.Header{}
+		}
+		if o.ws.Header == nil {
+			o.ws.Header = http.Header{}
+		}
+		o.http.Header.Add(key, value)
+		o.ws.Header.Add(key, value)
+	}
+}
+
+// WithUserAgent sets the `User-Agent` header of every HTTP request and of the websocket handshake.
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.http.UserAgent = userAgent
+		o.ws.UserAgent = userAgent
+	}
+}
+
+// WithRetryPolicy replaces the policy used to retry transient HTTP failures, nil disables retries.
+func WithRetryPolicy(policy *httpconn.RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.http.RetryPolicy = policy
+	}
+}
+
+// WithRateLimiter throttles HTTP requests with limiter, see ratelimit.New for a per endpoint and per method class limiter.
+func WithRateLimiter(limiter httpconn.RateLimiter) Option {
+	return func(o *clientOptions) {
+		o.http.RateLimiter = limiter
+	}
+}
+
+// WithLogger sets the logger receiving the diagnostics of the SDK, e.g. the retried HTTP requests and the lost
+// websocket connections, slog.Default() is used otherwise. Per-call logs are enabled separately with interceptor.Logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *clientOptions) {
+		o.http.Logger = logger
+		o.ws.Logger = logger
+	}
+}
+
+// WithInterceptors appends interceptors wrapping every call, the first interceptor is the outermost.
+// The headers of interceptor.StaticHeaders and interceptor.DynamicHeaders only apply to HTTP requests, WithHeader
+// also sets the headers of the websocket handshake.
+func WithInterceptors(interceptors ...interceptor.Interceptor) Option {
+	return func(o *clientOptions) {
+		o.http.Interceptors = append(o.http.Interceptors, interceptors...)
+		o.ws.Interceptors = append(o.ws.Interceptors, interceptors...)
+	}
+}
+
+// WithMaxResponseSize limits the size of an HTTP response body or a websocket message in bytes.
+func WithMaxResponseSize(size int64) Option {
+	return func(o *clientOptions) {
+		o.http.MaxResponseSize = size
+		o.ws.ReadLimit = size
+	}
+}
+
+// WithReconnectPolicy replaces the policy used to dial a lost websocket connection again, nil closes the
+// connection once it is lost instead.
+func WithReconnectPolicy(policy *wsconn.ReconnectPolicy) Option {
+	return func(o *clientOptions) {
+		o.ws.Reconnect = policy
+		o.ws.DisableReconnect = policy == nil
+	}
+}
+
+// WithKeepalivePolicy replaces the pings and deadlines detecting a dead websocket connection, which is then dialed
+// again. nil sends no ping and sets no deadline.
+func WithKeepalivePolicy(policy *wsconn.KeepalivePolicy) Option {
+	return func(o *clientOptions) {
+		o.ws.Keepalive = policy
+		o.ws.DisableKeepalive = policy == nil
+	}
+}
+
+// WithConnStateHandler calls fn on every state change of the websocket connection, e.g. when it is lost and
+// when it reconnects. fn must not block.
+func WithConnStateHandler(fn func(state wsconn.ConnState, err error)) Option {
+	return func(o *clientOptions) {
+		o.ws.OnStateChange = fn
+	}
+}
+
+// WithSubscriptionErrorHandler decides what subscriptions do with a notification carrying an error or failing to
+// decode: skip it, stop the subscription or issue it again. By default the error is logged and the notification skipped.
+func WithSubscriptionErrorHandler(handler SubscriptionErrorHandler) Option {
+	return func(o *clientOptions) {
+		o.subscriptionErrors = handler
+	}
+}
+
+// WithSubscriptionQueue configures the queue of the subscriptions made without WithQueue, see wsconn.QueueConfig.
+func WithSubscriptionQueue(queue wsconn.QueueConfig) Option {
+	return func(o *clientOptions) {
+		o.ws.Queue = queue
+	}
+}
+
+// WithPollConfig configures the poll intervals and page size of the subscriptions of a PollingClient.
+func WithPollConfig(cfg PollConfig) Option {
+	return func(o *clientOptions) {
+		o.poll = cfg
+	}
+}
+
+// WithPool routes HTTP requests to the best endpoint of pool, the rpc url passed to NewSuiClient is then ignored.
+// The caller owns the pool and should Close it once the client is no longer used. The health probes of the pool
+// carry the PoolConfig.Header, not the headers of WithHeader.
+func WithPool(pool *httpconn.Pool) Option {
+	return func(o *clientOptions) {
+		o.pool = pool
+	}
+}
+
+// WithDefaultObjectDataOptions sets the SuiObjectDataOptions of object reads that don't set any.
+func WithDefaultObjectDataOptions(options models.SuiObjectDataOptions) Option {
+	return func(o *clientOptions) {
+		o.objectDataOptions = &options
+	}
+}
+
+// WithDefaultTransactionBlockOptions sets the SuiTransactionBlockOptions of transaction reads and executions that don't set any.
+func WithDefaultTransactionBlockOptions(options models.SuiTransactionBlockOptions) Option {
+	return func(o *clientOptions) {
+		o.transactionBlockOptions = &options
+	}
+}
+
+// WithGasEstimation estimates the gas budget of the transactions built by the `unsafe_*` methods whose request
+// leaves GasBudget empty: the transaction is built with the DryRunBudget, dry run, and built again with the budget
+// estimated from its gas cost. A DryRunBudget rejected by the node, e.g. above the balance of the gas coin, is
+// lowered 10 times at a time down to 0.001 SUI.
+func WithGasEstimation(cfg GasEstimateConfig) Option {
+	return func(o *clientOptions) {
+		o.gasEstimate = &cfg
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+func TestOptions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	policy := httpconn.NoRetry()
+	noop := func(ctx context.Context, call *interceptor.Call, next interceptor.Invoker) error {
+		return next(ctx, call)
+	}
+	o := newClientOptions([]Option{
+		WithTimeout(3 * time.Second),
+		WithHeader("X-Api-Key", "key"),
+		WithUserAgent("agent"),
+		WithRetryPolicy(policy),
+		WithLogger(logger),
+		WithInterceptors(noop, noop),
+		WithMaxResponseSize(1 << 20),
+		WithConnectionPool(10, 5, 2, time.Minute),
+	})
+
+	if o.http.Timeout != 3*time.Second || o.ws.HandshakeTimeout != 3*time.Second {
+		t.Errorf("expected the timeout on both transports, got %v and %v", o.http.Timeout, o.ws.HandshakeTimeout)
+	}
+	if o.http.Header.Get("X-Api-Key") != "key" || o.ws.Header.Get("X-Api-Key") != "key" || o.http.UserAgent != "agent" || o.ws.UserAgent != "agent" {
+		t.Errorf("expected the headers on both transports, got %v and %v", o.http.Header, o.ws.Header)
+	}
+	if o.http.Logger != logger || o.ws.Logger != logger {
+		t.Error("expected the logger on both transports")
+	}
+	if len(o.http.Interceptors) != 2 || len(o.ws.Interceptors) != 2 {
+		t.Errorf("expected the interceptors on both transports, got %d and %d", len(o.http.Interceptors), len(o.ws.Interceptors))
+	}
+	if o.http.MaxResponseSize != 1<<20 || o.ws.ReadLimit != 1<<20 {
+		t.Errorf("expected the response size limit on both transports, got %d and %d", o.http.MaxResponseSize, o.ws.ReadLimit)
+	}
+	if o.http.RetryPolicy != policy || o.http.MaxIdleConns != 10 || o.http.MaxIdleConnsPerHost != 5 || o.http.MaxConnsPerHost != 2 || o.http.IdleConnTimeout != time.Minute {
+		t.Errorf("unexpected HTTP config %+v", o.http)
+	}
+
+	// the defaults of the HTTP transport are kept when no option overrides them
+	if d := newClientOptions(nil).http; d.RetryPolicy == nil || d.Timeout != httpconn.DefaultConfig().Timeout {
+		t.Errorf("expected the default HTTP config, got %+v", d)
+	}
+}
+
+func TestWithLoggerOnHTTP(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	srv.FailHTTP("suix_getReferenceGasPrice", http.StatusServiceUnavailable)
+	var logs bytes.Buffer
+	cli := NewSuiClient(srv.URL,
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithRetryPolicy(&httpconn.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	)
+	if _, err := cli.SuiXGetReferenceGasPrice(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), "suix_getReferenceGasPrice") {
+		t.Errorf("expected the retried request to be logged, got %q", logs.String())
+	}
+}
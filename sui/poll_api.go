@@ -0,0 +1,330 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+// PollConfig configures the polling of a PollingClient.
+type PollConfig struct {
+	// MinInterval is the delay between two polls while new items keep coming, 1s if 0.
+	MinInterval time.Duration
+	// MaxInterval caps the delay between two polls, which doubles on every poll returning nothing or failing, 30s if 0.
+	MaxInterval time.Duration
+	// PageSize is the number of items queried per poll, up to 50, 50 if 0.
+	PageSize uint64
+}
+
+func (c PollConfig) withDefaults() PollConfig {
+	if c.MinInterval <= 0 {
+		c.MinInterval = time.Second
+	}
+	if c.MaxInterval < c.MinInterval {
+		c.MaxInterval = 30 * time.Second
+		if c.MaxInterval < c.MinInterval {
+			c.MaxInterval = c.MinInterval
+		}
+	}
+	if c.PageSize == 0 || c.PageSize > 50 {
+		c.PageSize = 50
+	}
+	return c
+}
+
+// PollingClient implements ISuiWebsocketAPI by polling `suix_queryEvents` and `suix_queryTransactionBlocks`
+// over HTTP, for the nodes that don't serve websocket subscriptions. Consumers of ISubscribeAPI can switch
+// between a WebsocketClient and a PollingClient without any other change. The methods of ISuiAPI are those of the
+// client it polls.
+type PollingClient struct {
+	ISuiAPI
+	options *clientOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+	nextID  atomic.Int64
+}
+
+// NewSuiPollingClient instantiates a PollingClient querying cli. The polling is configured by opts,
+// e.g. WithPollConfig or WithSubscriptionErrorHandler.
+func NewSuiPollingClient(cli ISuiAPI, opts ...Option) ISuiWebsocketAPI {
+	c := &PollingClient{
+		ISuiAPI: cli,
+		options: newClientOptions(opts),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c
+}
+
+// State returns wsconn.StateConnected until the client is closed.
+func (c *PollingClient) State() wsconn.ConnState {
+	if c.ctx.Err() != nil {
+		return wsconn.StateClosed
+	}
+	return wsconn.StateConnected
+}
+
+// Health returns the state of the client, a polling client has no connection to report on.
+func (c *PollingClient) Health() wsconn.Health {
+	return wsconn.Health{State: c.State()}
+}
+
+// Close stops the polling, the subscriptions end with wsconn.ErrClosed.
+func (c *PollingClient) Close() error {
+	c.cancel()
+	return nil
+}
+
+// SubscribeEvent polls `suix_queryEvents` for the events matching the filter of req, from the cursor passed with
+// WithCursor or from the latest event otherwise.
+func (c *PollingClient) SubscribeEvent(ctx context.Context, req models.SuiXSubscribeEventsRequest, msgCh chan models.SuiEventResponse, opts ...SubscribeOption) (*Subscription, error) {
+	return poll(ctx, c, pollSpec[models.SuiEventResponse]{
+		method: "suix_queryEvents",
+		query: func(ctx context.Context, cursor interface{}, limit uint64, descending bool) ([]models.SuiEventResponse, interface{}, bool, error) {
+			rsp, err := c.ISuiAPI.SuiXQueryEvents(ctx, models.SuiXQueryEventsRequest{
+				SuiEventFilter:  req.SuiEventFilter,
+				Cursor:          cursor,
+				Limit:           limit,
+				DescendingOrder: descending,
+			})
+			if err != nil {
+				return nil, nil, false, err
+			}
+			var next interface{}
+			if rsp.NextCursor != (models.EventId{}) {
+				next = rsp.NextCursor
+			}
+			return rsp.Data, next, rsp.HasNextPage, nil
+		},
+		key: func(event models.SuiEventResponse) string {
+			return event.Id.TxDigest + ":" + event.Id.EventSeq
+		},
+		cursor: eventCursor,
+	}, msgCh, opts)
+}
+
+// SubscribeTransaction polls `suix_queryTransactionBlocks` for the effects of the transactions matching the filter
+// of req, from the cursor passed with WithCursor or from the latest transaction otherwise.
+func (c *PollingClient) SubscribeTransaction(ctx context.Context, req models.SuiXSubscribeTransactionsRequest, msgCh chan models.SuiEffects, opts ...SubscribeOption) (*Subscription, error) {
+	filter, err := transactionFilter(req.TransactionFilter)
+	if err != nil {
+		return nil, err
+	}
+	return poll(ctx, c, pollSpec[models.SuiEffects]{
+		method: "suix_queryTransactionBlocks",
+		query: func(ctx context.Context, cursor interface{}, limit uint64, descending bool) ([]models.SuiEffects, interface{}, bool, error) {
+			rsp, err := c.ISuiAPI.SuiXQueryTransactionBlocks(ctx, models.SuiXQueryTransactionBlocksRequest{
+				SuiTransactionBlockResponseQuery: models.SuiTransactionBlockResponseQuery{
+					TransactionFilter: filter,
+					Options:           models.SuiTransactionBlockOptions{ShowEffects: true},
+				},
+				Cursor:          cursor,
+				Limit:           limit,
+				DescendingOrder: descending,
+			})
+			if err != nil {
+				return nil, nil, false, err
+			}
+			effects := make([]models.SuiEffects, 0, len(rsp.Data))
+			for _, tx := range rsp.Data {
+				if tx.Effects.TransactionDigest == "" {
+					tx.Effects.TransactionDigest = tx.Digest
+				}
+				effects = append(effects, tx.Effects)
+			}
+			var next interface{}
+			if rsp.NextCursor != "" {
+				next = rsp.NextCursor
+			}
+			return effects, next, rsp.HasNextPage, nil
+		},
+		key:    func(effects models.SuiEffects) string { return effects.TransactionDigest },
+		cursor: transactionCursor,
+	}, msgCh, opts)
+}
+
+// transactionFilter converts the filter of a transaction subscription to the filter of a transaction query.
+func transactionFilter(filter interface{}) (models.TransactionFilter, error) {
+	if f, ok := filter.(models.TransactionFilter); ok {
+		return f, nil
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var f models.TransactionFilter
+	err = json.Unmarshal(data, &f)
+	return f, err
+}
+
+// pollSpec describes how to poll the items of a subscription.
+type pollSpec[T any] struct {
+	method string
+	// query returns a page of items after cursor, the cursor of the next page and whether it exists
+	query  func(ctx context.Context, cursor interface{}, limit uint64, descending bool) ([]T, interface{}, bool, error)
+	key    func(T) string
+	cursor func(T) interface{}
+}
+
+// pollSource is the subscriptionSource of a polling subscription.
+type pollSource struct {
+	id     int64
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (p *pollSource) ID() int64 {
+	return p.id
+}
+
+func (p *pollSource) Unsubscribe() error {
+	p.cancel()
+	<-p.done
+	return nil
+}
+
+// QueueStats returns no queue, polling waits for the consumer instead of queueing.
+func (p *pollSource) QueueStats() wsconn.QueueStats {
+	return wsconn.QueueStats{}
+}
+
+func poll[T any](ctx context.Context, c *PollingClient, spec pollSpec[T], msgCh chan T, opts []SubscribeOption) (*Subscription, error) {
+	if c.ctx.Err() != nil {
+		return nil, wsconn.ErrClosed
+	}
+	var options subscribeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	cfg := c.options.poll.withDefaults()
+
+	cursor := options.cursor
+	if cursor == nil {
+		// start after the latest item
+		latest, _, _, err := spec.query(ctx, nil, 1, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(latest) > 0 {
+			cursor = spec.cursor(latest[0])
+		}
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.ctx, cancel)
+	src := &pollSource{id: c.nextID.Add(1), cancel: cancel, done: make(chan struct{})}
+	s := newSubscription(src, spec.method)
+	if cursor != nil {
+		s.setCursor(cursor)
+	}
+
+	go func() {
+		var endErr error
+		defer func() {
+			stop()
+			cancel()
+			if c.ctx.Err() != nil && endErr == nil {
+				endErr = wsconn.ErrClosed
+			}
+			s.end(endErr)
+			close(src.done)
+		}()
+		endErr = runPoll(pollCtx, s, spec, cfg, c.options.subscriptionErrorHandler(), cursor, msgCh)
+	}()
+	return s, nil
+}
+
+// pollQuery is the Message of the SubscriptionError of a failed poll.
+type pollQuery struct {
+	Cursor interface{} `json:"cursor"`
+	Limit  uint64      `json:"limit"`
+}
+
+// runPoll polls the items after cursor and sends them to msgCh until ctx is done, the interval between two polls
+// grows while there is nothing new and is reset once items come. A failed poll is passed to handler: SkipMessage
+// backs off before polling the page again, RetrySubscription polls it again after cfg.MinInterval.
+func runPoll[T any](ctx context.Context, s *Subscription, spec pollSpec[T], cfg PollConfig, handler SubscriptionErrorHandler, cursor interface{}, msgCh chan T) error {
+	seen := newSeenSet(4 * int(cfg.PageSize))
+	interval := cfg.MinInterval
+	for {
+		items, next, hasNext, err := spec.query(ctx, cursor, cfg.PageSize, false)
+		if ctx.Err() != nil {
+			return nil
+		}
+		retry := false
+		if err != nil {
+			query, _ := json.Marshal(pollQuery{Cursor: cursor, Limit: cfg.PageSize})
+			subErr := &SubscriptionError{Method: spec.method, Message: query, Err: err}
+			switch handler(subErr) {
+			case StopSubscription:
+				return subErr
+			case RetrySubscription:
+				s.retries.Add(1)
+				retry = true
+			}
+			hasNext = false
+		}
+
+		for _, item := range items {
+			s.received.Add(1)
+			if !seen.add(spec.key(item)) {
+				s.dropped.Add(1)
+				continue
+			}
+			select {
+			case msgCh <- item:
+				s.delivered.Add(1)
+				s.setCursor(spec.cursor(item))
+			case <-ctx.Done():
+				return nil
+			}
+			cursor = spec.cursor(item)
+		}
+		if next != nil {
+			cursor = next
+		}
+
+		switch {
+		case hasNext:
+			continue
+		case len(items) > 0 || retry:
+			interval = cfg.MinInterval
+		default:
+			interval = min(2*interval, cfg.MaxInterval)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// seenSet remembers the last keys added to it, to deduplicate the items of overlapping pages.
+type seenSet struct {
+	keys  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newSeenSet(size int) *seenSet {
+	return &seenSet{keys: make(map[string]struct{}, size), order: make([]string, size)}
+}
+
+// add returns false if key was already seen.
+func (s *seenSet) add(key string) bool {
+	if _, ok := s.keys[key]; ok {
+		return false
+	}
+	delete(s.keys, s.order[s.next])
+	s.order[s.next] = key
+	s.next = (s.next + 1) % len(s.order)
+	s.keys[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,202 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+// eventLog serves `suix_queryEvents` pages from a growing list of events.
+type eventLog struct {
+	mu     sync.Mutex
+	events []models.SuiEventResponse
+}
+
+func (l *eventLog) emit(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := 0; i < n; i++ {
+		seq := len(l.events)
+		l.events = append(l.events, models.SuiEventResponse{Id: models.EventId{TxDigest: fmt.Sprintf("tx%d", seq), EventSeq: "0"}})
+	}
+}
+
+func (l *eventLog) query(params json.RawMessage) (interface{}, error) {
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, err
+	}
+	var cursor *models.EventId
+	var limit int
+	var descending bool
+	_ = json.Unmarshal(args[1], &cursor)
+	_ = json.Unmarshal(args[2], &limit)
+	_ = json.Unmarshal(args[3], &descending)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	page := models.PaginatedEventsResponse{Data: []models.SuiEventResponse{}}
+	if descending {
+		if len(l.events) > 0 {
+			page.Data = append(page.Data, l.events[len(l.events)-1])
+		}
+		return page, nil
+	}
+	start := 0
+	for i, event := range l.events {
+		if cursor != nil && event.Id == *cursor {
+			start = i + 1
+		}
+	}
+	end := min(start+limit, len(l.events))
+	page.Data = append(page.Data, l.events[start:end]...)
+	page.HasNextPage = end < len(l.events)
+	if end > start {
+		page.NextCursor = l.events[end-1].Id
+	}
+	return page, nil
+}
+
+func TestPollingClient(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	log := &eventLog{}
+	log.emit(3)
+	srv.Handle("suix_queryEvents", log.query)
+
+	var ws ISuiWebsocketAPI = NewSuiPollingClient(NewSuiClient(srv.URL), WithPollConfig(PollConfig{
+		MinInterval: 5 * time.Millisecond,
+		MaxInterval: 20 * time.Millisecond,
+		PageSize:    2,
+	}))
+	defer ws.Close()
+
+	receive := func(events chan models.SuiEventResponse, want ...string) {
+		t.Helper()
+		for _, digest := range want {
+			select {
+			case event := <-events:
+				if event.Id.TxDigest != digest {
+					t.Fatalf("expected %s, got %s", digest, event.Id.TxDigest)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for %s", digest)
+			}
+		}
+	}
+
+	// the events before the subscription are skipped
+	events := make(chan models.SuiEventResponse)
+	sub, err := ws.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{
+		SuiEventFilter: map[string]interface{}{"All": []string{}},
+	}, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.emit(5)
+	receive(events, "tx3", "tx4", "tx5", "tx6", "tx7")
+	cursor := sub.Cursor()
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	if stats := sub.Stats(); stats.Delivered != 5 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// a subscription resumes from a cursor
+	log.emit(2)
+	events = make(chan models.SuiEventResponse)
+	sub, err = ws.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{
+		SuiEventFilter: map[string]interface{}{"All": []string{}},
+	}, events, WithCursor(cursor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive(events, "tx8", "tx9")
+
+	ws.Close()
+	select {
+	case err := <-sub.Err():
+		if !errors.Is(err, wsconn.ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription didn't end with the client")
+	}
+}
+
+func TestPollingClientRetry(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	log := &eventLog{}
+	log.emit(1)
+	// the polls fail 5 times once subscribed
+	var mu sync.Mutex
+	failures := 0
+	srv.Handle("suix_queryEvents", func(params json.RawMessage) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			return nil, errors.New("node overloaded")
+		}
+		return log.query(params)
+	})
+
+	subErrs := make(chan *SubscriptionError, 10)
+	ws := NewSuiPollingClient(NewSuiClient(srv.URL), WithPollConfig(PollConfig{
+		MinInterval: 5 * time.Millisecond,
+		MaxInterval: time.Minute,
+	}), WithSubscriptionErrorHandler(func(err *SubscriptionError) SubscriptionAction {
+		subErrs <- err
+		return RetrySubscription
+	}))
+	defer ws.Close()
+
+	events := make(chan models.SuiEventResponse)
+	sub, err := ws.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{
+		SuiEventFilter: map[string]interface{}{"All": []string{}},
+	}, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	failures = 5
+	mu.Unlock()
+	log.emit(1)
+
+	// the failed page is polled again without backing off
+	select {
+	case event := <-events:
+		if event.Id.TxDigest != "tx1" {
+			t.Fatalf("expected tx1, got %s", event.Id.TxDigest)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the failed poll was not retried")
+	}
+	if stats := sub.Stats(); stats.Retries != 5 {
+		t.Errorf("expected 5 retries, got %+v", stats)
+	}
+	subErr := <-subErrs
+	var query struct {
+		Cursor models.EventId `json:"cursor"`
+		Limit  uint64         `json:"limit"`
+	}
+	if err := json.Unmarshal(subErr.Message, &query); err != nil || query.Cursor.TxDigest != "tx0" || query.Limit != 50 {
+		t.Errorf("unexpected message %s, %v", subErr.Message, err)
+	}
+	if subErr.Method != "suix_queryEvents" || !strings.Contains(subErr.Error(), "node overloaded") {
+		t.Errorf("unexpected error %v", subErr)
+	}
+}
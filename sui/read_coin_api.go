@@ -20,7 +20,7 @@ type IReadCoinFromSuiAPI interface {
 }
 
 type suiReadCoinFromSuiImpl struct {
-	conn *httpconn.HttpConn
+	conn Transport
 }
 
 // SuiXGetBalance implements the method `suix_getBalance`, gets the total Coin balance for each coin type owned by an address.
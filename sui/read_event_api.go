@@ -16,7 +16,7 @@ type IReadEventFromSuiAPI interface {
 }
 
 type suiReadEventFromSuiImpl struct {
-	conn *httpconn.HttpConn
+	conn Transport
 }
 
 // SuiGetEvents implements the method `sui_getEvents`, gets transaction events.
@@ -16,7 +16,7 @@ type IReadMoveFromSuiAPI interface {
 }
 
 type suiReadMoveFromSuiImpl struct {
-	conn *httpconn.HttpConn
+	conn Transport
 }
 
 // SuiGetMoveFunctionArgTypes implements method `sui_getMoveFunctionArgTypes`, return the argument types of a Move function based on normalized type.
@@ -14,7 +14,7 @@ type IReadNameServiceFromSuiAPI interface {
 }
 
 type suiReadNameServiceFromSuiImpl struct {
-	conn *httpconn.HttpConn
+	conn Transport
 }
 
 // SuiXResolveNameServiceAddress implements the method `suix_resolveNameServiceAddress`, get the resolved address given resolver and name.
@@ -21,7 +21,8 @@ type IReadObjectFromSuiAPI interface {
 }
 
 type suiReadObjectFromSuiImpl struct {
-	conn *httpconn.HttpConn
+	conn    Transport
+	options *clientOptions
 }
 
 // SuiGetObject implements the method `sui_getObject`, gets the object information for a specified object.
@@ -31,7 +32,7 @@ func (s *suiReadObjectFromSuiImpl) SuiGetObject(ctx context.Context, req models.
 		Method: "sui_getObject",
 		Params: []interface{}{
 			req.ObjectId,
-			req.Options,
+			s.options.objectOptions(req.Options),
 		},
 	})
 	if err != nil {
@@ -46,6 +47,7 @@ func (s *suiReadObjectFromSuiImpl) SuiXGetOwnedObjects(ctx context.Context, req
 	if err := validate.ValidateStruct(req); err != nil {
 		return rsp, err
 	}
+	req.Query.Options = s.options.objectOptions(req.Query.Options)
 	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
 		Method: "suix_getOwnedObjects",
 		Params: []interface{}{
@@ -68,7 +70,7 @@ func (s *suiReadObjectFromSuiImpl) SuiMultiGetObjects(ctx context.Context, req m
 		Method: "sui_multiGetObjects",
 		Params: []interface{}{
 			req.ObjectIds,
-			req.Options,
+			s.options.objectOptions(req.Options),
 		},
 	})
 	if err != nil {
@@ -122,7 +124,7 @@ func (s *suiReadObjectFromSuiImpl) SuiTryGetPastObject(ctx context.Context, req
 		Params: []interface{}{
 			req.ObjectId,
 			req.Version,
-			req.Options,
+			s.options.objectOptions(req.Options),
 		},
 	})
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 type IReadSystemFromSuiAPI interface {
 	SuiGetCheckpoint(ctx context.Context, req models.SuiGetCheckpointRequest) (models.CheckpointResponse, error)
 	SuiGetCheckpoints(ctx context.Context, req models.SuiGetCheckpointsRequest) (models.PaginatedCheckpointsResponse, error)
+	SuiGetCheckpointsStream(ctx context.Context, req models.SuiGetCheckpointsRequest, fn func(models.CheckpointResponse) error) (models.PaginatedCheckpointsResponse, error)
 	SuiGetLatestCheckpointSequenceNumber(ctx context.Context) (uint64, error)
 	SuiXGetReferenceGasPrice(ctx context.Context) (uint64, error)
 	SuiXGetCommitteeInfo(ctx context.Context, req models.SuiXGetCommitteeInfoRequest) (models.SuiXGetCommitteeInfoResponse, error)
@@ -26,7 +27,7 @@ type IReadSystemFromSuiAPI interface {
 }
 
 type suiReadSystemFromSuiImpl struct {
-	conn *httpconn.HttpConn
+	conn Transport
 }
 
 // SuiGetCheckpoint implements the method `sui_getCheckpoint`, gets a checkpoint.
@@ -66,6 +67,24 @@ func (s *suiReadSystemFromSuiImpl) SuiGetCheckpoints(ctx context.Context, req mo
 	return rsp, nil
 }
 
+// SuiGetCheckpointsStream is SuiGetCheckpoints decoding one checkpoint at a time and passing it to fn.
+// The returned page holds the cursor of the next page and no data.
+func (s *suiReadSystemFromSuiImpl) SuiGetCheckpointsStream(ctx context.Context, req models.SuiGetCheckpointsRequest, fn func(models.CheckpointResponse) error) (models.PaginatedCheckpointsResponse, error) {
+	var rsp models.PaginatedCheckpointsResponse
+	if err := validate.ValidateStruct(req); err != nil {
+		return rsp, err
+	}
+	err := streamArray(ctx, s.conn, httpconn.Operation{
+		Method: "sui_getCheckpoints",
+		Params: []interface{}{
+			req.Cursor,
+			req.Limit,
+			req.DescendingOrder,
+		},
+	}, "data", &rsp, fn)
+	return rsp, err
+}
+
 // SuiGetLatestCheckpointSequenceNumber implements the method `sui_getLatestCheckpointSequenceNumber`, gets the sequence number of the latest checkpoint that has been executed.
 func (s *suiReadSystemFromSuiImpl) SuiGetLatestCheckpointSequenceNumber(ctx context.Context) (uint64, error) {
 	var rsp uint64
@@ -15,12 +15,15 @@ type IReadTransactionFromSuiAPI interface {
 	SuiGetTransactionBlock(ctx context.Context, req models.SuiGetTransactionBlockRequest) (models.SuiTransactionBlockResponse, error)
 	SuiMultiGetTransactionBlocks(ctx context.Context, req models.SuiMultiGetTransactionBlocksRequest) (models.SuiMultiGetTransactionBlocksResponse, error)
 	SuiXQueryTransactionBlocks(ctx context.Context, req models.SuiXQueryTransactionBlocksRequest) (models.SuiXQueryTransactionBlocksResponse, error)
+	SuiMultiGetTransactionBlocksStream(ctx context.Context, req models.SuiMultiGetTransactionBlocksRequest, fn func(models.SuiTransactionBlockResponse) error) error
+	SuiXQueryTransactionBlocksStream(ctx context.Context, req models.SuiXQueryTransactionBlocksRequest, fn func(models.SuiTransactionBlockResponse) error) (models.SuiXQueryTransactionBlocksResponse, error)
 	SuiDryRunTransactionBlock(ctx context.Context, req models.SuiDryRunTransactionBlockRequest) (models.SuiTransactionBlockResponse, error)
 	SuiDevInspectTransactionBlock(ctx context.Context, req models.SuiDevInspectTransactionBlockRequest) (models.SuiTransactionBlockResponse, error)
 }
 
 type suiReadTransactionFromSuiImpl struct {
-	conn *httpconn.HttpConn
+	conn    Transport
+	options *clientOptions
 }
 
 // SuiGetTotalTransactionBlocks implements the method `sui_getTotalTransactionBlocks`, gets the total number of transactions known to the node.
@@ -43,7 +46,7 @@ func (s *suiReadTransactionFromSuiImpl) SuiGetTransactionBlock(ctx context.Conte
 		Method: "sui_getTransactionBlock",
 		Params: []interface{}{
 			req.Digest,
-			req.Options,
+			s.options.transactionOptions(req.Options),
 		},
 	})
 	if err != nil {
@@ -60,7 +63,7 @@ func (s *suiReadTransactionFromSuiImpl) SuiMultiGetTransactionBlocks(ctx context
 		Method: "sui_multiGetTransactionBlocks",
 		Params: []interface{}{
 			req.Digests,
-			req.Options,
+			s.options.transactionOptions(req.Options),
 		},
 	})
 	if err != nil {
@@ -76,10 +79,12 @@ func (s *suiReadTransactionFromSuiImpl) SuiXQueryTransactionBlocks(ctx context.C
 	if err := validate.ValidateStruct(req); err != nil {
 		return rsp, err
 	}
+	query := req.SuiTransactionBlockResponseQuery
+	query.Options = s.options.transactionOptions(query.Options)
 	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
 		Method: "suix_queryTransactionBlocks",
 		Params: []interface{}{
-			req.SuiTransactionBlockResponseQuery,
+			query,
 			req.Cursor,
 			req.Limit,
 			req.DescendingOrder,
@@ -92,6 +97,39 @@ func (s *suiReadTransactionFromSuiImpl) SuiXQueryTransactionBlocks(ctx context.C
 	return rsp, nil
 }
 
+// SuiMultiGetTransactionBlocksStream is SuiMultiGetTransactionBlocks decoding one transaction at a time and passing it to fn,
+// so that large responses are never held in memory at once.
+func (s *suiReadTransactionFromSuiImpl) SuiMultiGetTransactionBlocksStream(ctx context.Context, req models.SuiMultiGetTransactionBlocksRequest, fn func(models.SuiTransactionBlockResponse) error) error {
+	return streamArray(ctx, s.conn, httpconn.Operation{
+		Method: "sui_multiGetTransactionBlocks",
+		Params: []interface{}{
+			req.Digests,
+			s.options.transactionOptions(req.Options),
+		},
+	}, "", nil, fn)
+}
+
+// SuiXQueryTransactionBlocksStream is SuiXQueryTransactionBlocks decoding one transaction at a time and passing it to fn.
+// The returned page holds the cursor of the next page and no data.
+func (s *suiReadTransactionFromSuiImpl) SuiXQueryTransactionBlocksStream(ctx context.Context, req models.SuiXQueryTransactionBlocksRequest, fn func(models.SuiTransactionBlockResponse) error) (models.SuiXQueryTransactionBlocksResponse, error) {
+	var rsp models.SuiXQueryTransactionBlocksResponse
+	if err := validate.ValidateStruct(req); err != nil {
+		return rsp, err
+	}
+	query := req.SuiTransactionBlockResponseQuery
+	query.Options = s.options.transactionOptions(query.Options)
+	err := streamArray(ctx, s.conn, httpconn.Operation{
+		Method: "suix_queryTransactionBlocks",
+		Params: []interface{}{
+			query,
+			req.Cursor,
+			req.Limit,
+			req.DescendingOrder,
+		},
+	}, "data", &rsp, fn)
+	return rsp, err
+}
+
 // SuiDryRunTransactionBlock implements the method `sui_dryRunTransactionBlock`, gets transaction execution effects including the gas cost summary, while the effects are not committed to the chain.
 func (s *suiReadTransactionFromSuiImpl) SuiDryRunTransactionBlock(ctx context.Context, req models.SuiDryRunTransactionBlockRequest) (models.SuiTransactionBlockResponse, error) {
 	var rsp models.SuiTransactionBlockResponse
@@ -3,88 +3,312 @@ package sui
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
 
-	"github.com/tidwall/gjson"
 	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
 	"github.com/yasir7ca/sui-go-sdk/models"
 )
 
 type ISubscribeAPI interface {
-	SubscribeEvent(ctx context.Context, req models.SuiXSubscribeEventsRequest, msgCh chan models.SuiEventResponse) error
-	SubscribeTransaction(ctx context.Context, req models.SuiXSubscribeTransactionsRequest, msgCh chan models.SuiEffects) error
+	SubscribeEvent(ctx context.Context, req models.SuiXSubscribeEventsRequest, msgCh chan models.SuiEventResponse, opts ...SubscribeOption) (*Subscription, error)
+	SubscribeTransaction(ctx context.Context, req models.SuiXSubscribeTransactionsRequest, msgCh chan models.SuiEffects, opts ...SubscribeOption) (*Subscription, error)
 }
 
 type suiSubscribeImpl struct {
-	conn *wsconn.WsConn
+	conn    *wsconn.WsConn
+	options *clientOptions
 }
 
-// SubscribeEvent implements the method `suix_subscribeEvent`, subscribe to a stream of Sui event.
-func (s *suiSubscribeImpl) SubscribeEvent(ctx context.Context, req models.SuiXSubscribeEventsRequest, msgCh chan models.SuiEventResponse) error {
-	rsp := make(chan []byte, 10)
-	err := s.conn.Call(ctx, wsconn.CallOp{
-		Method: "suix_subscribeEvent",
-		Params: []interface{}{
-			req.SuiEventFilter,
-		},
-	}, rsp)
+// SubscribeOption configures a single subscription.
+type SubscribeOption func(*subscribeOptions)
+
+type subscribeOptions struct {
+	queue  wsconn.QueueConfig
+	cursor interface{}
+}
+
+// WithQueue configures the queue holding the notifications of the subscription until msgCh receives them, and
+// what to do when it is full: block, drop the oldest or the newest notification, or spill to a file.
+// It overrides WithSubscriptionQueue.
+func WithQueue(queue wsconn.QueueConfig) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.queue = queue
+	}
+}
+
+// WithCursor resumes a polling subscription after cursor, the models.EventId of an event or the digest of a
+// transaction as returned by Subscription.Cursor. Websocket subscriptions can't resume and ignore it.
+func WithCursor(cursor interface{}) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.cursor = cursor
+	}
+}
+
+// SubscriptionAction is what a subscription does with a notification it failed to deliver.
+type SubscriptionAction int
+
+const (
+	// SkipMessage drops the notification and keeps the subscription.
+	SkipMessage SubscriptionAction = iota
+	// StopSubscription unsubscribes and ends the subscription with the error.
+	StopSubscription
+	// RetrySubscription drops the notification and issues the subscription again.
+	RetrySubscription
+)
+
+// SubscriptionErrorHandler decides what a subscription does with a notification carrying an error or failing to
+// decode, or with a failed poll of a PollingClient: SkipMessage backs off before polling the page again and
+// RetrySubscription polls it again without backing off. It is called from the goroutine delivering the
+// notifications of the subscription.
+type SubscriptionErrorHandler func(err *SubscriptionError) SubscriptionAction
+
+// SubscriptionError is a notification a subscription failed to deliver.
+type SubscriptionError struct {
+	// Method is the subscribe method, e.g. `suix_subscribeEvent`.
+	Method string
+	// Message is the notification as received. For a poll of a PollingClient, it is the failed query: the cursor
+	// and limit of the page, e.g. `{"cursor":null,"limit":50}`.
+	Message json.RawMessage
+	// Err is the *models.JsonRPCError sent by the node, or the decoding error. For a poll of a PollingClient, it is
+	// the error of the query.
+	Err error
+}
+
+func (e *SubscriptionError) Error() string {
+	return fmt.Sprintf("sui subscription %s: %v", e.Method, e.Err)
+}
+
+func (e *SubscriptionError) Unwrap() error {
+	return e.Err
+}
+
+// SubscriptionStats are the counters of a subscription.
+type SubscriptionStats struct {
+	// Received is the number of notifications received.
+	Received uint64
+	// Delivered is the number of notifications sent to the channel of the subscription.
+	Delivered uint64
+	// Dropped is the number of notifications dropped because they carried an error or failed to decode, or were
+	// polled twice.
+	Dropped uint64
+	// Retries is the number of times the subscription was issued again, or its failed poll retried, by
+	// RetrySubscription.
+	Retries uint64
+	// Queue are the counters of the queue of the subscription: its depth and the notifications dropped because
+	// it was full.
+	Queue wsconn.QueueStats
+}
+
+// subscriptionSource is the transport of a Subscription, a websocket subscription or a poller.
+type subscriptionSource interface {
+	ID() int64
+	Unsubscribe() error
+	QueueStats() wsconn.QueueStats
+}
+
+// Subscription is an active subscription made with SubscribeEvent or SubscribeTransaction, pushed by a websocket
+// client or polled by a polling client.
+type Subscription struct {
+	src    subscriptionSource
+	method string
+	err    chan error
+	done   chan struct{}
+
+	received  atomic.Uint64
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	retries   atomic.Uint64
+
+	mu     sync.Mutex
+	cursor interface{}
+}
+
+func newSubscription(src subscriptionSource, method string) *Subscription {
+	return &Subscription{
+		src:    src,
+		method: method,
+		err:    make(chan error, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// ID returns the id of the subscription, the id of a websocket subscription changes on every reconnection.
+func (s *Subscription) ID() int64 {
+	return s.src.ID()
+}
+
+// Unsubscribe stops the subscription and cancels it on the node. It can be called several times.
+func (s *Subscription) Unsubscribe() error {
+	return s.src.Unsubscribe()
+}
+
+// Cursor returns the cursor of the last notification sent to the channel of the subscription, the models.EventId
+// of an event or the digest of a transaction, or nil if none was sent. Passed to WithCursor, it resumes a polling
+// subscription after that notification.
+func (s *Subscription) Cursor() interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursor
+}
+
+func (s *Subscription) setCursor(cursor interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursor = cursor
+}
+
+// end ends the subscription with err, if any.
+func (s *Subscription) end(err error) {
 	if err != nil {
-		return err
+		s.err <- err
 	}
+	close(s.err)
+	close(s.done)
+}
 
-	go func() {
-		for {
-			select {
-			case messageData := <-rsp:
-				var result models.SuiEventResponse
-				if gjson.ParseBytes(messageData).Get("error").Exists() {
-					log.Fatal(gjson.ParseBytes(messageData).Get("error").String())
-				}
+// Err returns a channel receiving the error ending the subscription: the error of a notification stopping it
+// through StopSubscription, or wsconn.ErrClosed once the websocket client is closed. It is closed without an
+// error when the subscription is unsubscribed or its context is done.
+func (s *Subscription) Err() <-chan error {
+	return s.err
+}
 
-				err := json.Unmarshal([]byte(gjson.ParseBytes(messageData).Get("params.result").String()), &result)
-				if err != nil {
-					log.Fatal(err)
-				}
+// Done returns a channel closed once the subscription ended, nothing is sent to its channel after it is closed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
 
-				msgCh <- result
-			}
-		}
-	}()
+// Stats returns the counters of the subscription.
+func (s *Subscription) Stats() SubscriptionStats {
+	return SubscriptionStats{
+		Received:  s.received.Load(),
+		Delivered: s.delivered.Load(),
+		Dropped:   s.dropped.Load(),
+		Retries:   s.retries.Load(),
+		Queue:     s.src.QueueStats(),
+	}
+}
 
-	return nil
+// SubscribeEvent implements the method `suix_subscribeEvent`, subscribe to a stream of Sui event.
+// The subscription ends when it is unsubscribed, ctx is done or the websocket client is closed, msgCh is never closed.
+func (s *suiSubscribeImpl) SubscribeEvent(ctx context.Context, req models.SuiXSubscribeEventsRequest, msgCh chan models.SuiEventResponse, opts ...SubscribeOption) (*Subscription, error) {
+	return subscribe(ctx, s, wsconn.CallOp{
+		Method: "suix_subscribeEvent",
+		Params: []interface{}{
+			req.SuiEventFilter,
+		},
+	}, msgCh, eventCursor, opts)
 }
 
 // SubscribeTransaction implements the method `suix_subscribeTransaction`, subscribe to a stream of Sui transaction effects.
-func (s *suiSubscribeImpl) SubscribeTransaction(ctx context.Context, req models.SuiXSubscribeTransactionsRequest, msgCh chan models.SuiEffects) error {
-	rsp := make(chan []byte, 10)
-	err := s.conn.Call(ctx, wsconn.CallOp{
+// The subscription ends when it is unsubscribed, ctx is done or the websocket client is closed, msgCh is never closed.
+func (s *suiSubscribeImpl) SubscribeTransaction(ctx context.Context, req models.SuiXSubscribeTransactionsRequest, msgCh chan models.SuiEffects, opts ...SubscribeOption) (*Subscription, error) {
+	return subscribe(ctx, s, wsconn.CallOp{
 		Method: "suix_subscribeTransaction",
 		Params: []interface{}{
 			req.TransactionFilter,
 		},
-	}, rsp)
+	}, msgCh, transactionCursor, opts)
+}
+
+func eventCursor(event models.SuiEventResponse) interface{} {
+	return event.Id
+}
+
+func transactionCursor(effects models.SuiEffects) interface{} {
+	return effects.TransactionDigest
+}
+
+// subscribe subscribes with op and decodes the result of every notification into msgCh until the subscription ends.
+func subscribe[T any](ctx context.Context, s *suiSubscribeImpl, op wsconn.CallOp, msgCh chan T, cursor func(T) interface{}, opts []SubscribeOption) (*Subscription, error) {
+	options := subscribeOptions{queue: s.options.ws.Queue}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	rsp := make(chan []byte)
+	sub, err := s.conn.SubscribeWithQueue(ctx, op, rsp, options.queue)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	go func() {
-		for {
-			select {
-			case messageData := <-rsp:
-				var result models.SuiEffects
-				if gjson.ParseBytes(messageData).Get("error").Exists() {
-					log.Fatal(gjson.ParseBytes(messageData).Get("error").String())
-				}
+	subscription := newSubscription(sub, op.Method)
+	go deliver(ctx, subscription, sub, s.options.subscriptionErrorHandler(), rsp, msgCh, cursor)
+	return subscription, nil
+}
+
+type notification struct {
+	Error  *models.JsonRPCError `json:"error"`
+	Params struct {
+		Result json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+// deliver decodes the notifications received on rsp into msgCh until the subscription ends.
+func deliver[T any](ctx context.Context, s *Subscription, sub *wsconn.Subscription, handler SubscriptionErrorHandler, rsp chan []byte, msgCh chan T, cursor func(T) interface{}) {
+	var endErr error
+	defer func() {
+		s.end(endErr)
+	}()
 
-				err := json.Unmarshal([]byte(gjson.ParseBytes(messageData).Get("params.result").String()), &result)
-				if err != nil {
-					log.Fatal(err)
+	for {
+		select {
+		case messageData := <-rsp:
+			s.received.Add(1)
+			result, err := decodeNotification[T](messageData)
+			if err != nil {
+				s.dropped.Add(1)
+				subErr := &SubscriptionError{Method: s.method, Message: messageData, Err: err}
+				switch handler(subErr) {
+				case StopSubscription:
+					_ = sub.Unsubscribe()
+					endErr = subErr
+					return
+				case RetrySubscription:
+					s.retries.Add(1)
+					if err := sub.Resubscribe(ctx); err != nil {
+						_ = sub.Unsubscribe()
+						endErr = err
+						return
+					}
 				}
+				continue
+			}
 
-				msgCh <- result
+			select {
+			case msgCh <- result:
+				s.delivered.Add(1)
+				s.setCursor(cursor(result))
+			case <-sub.Done():
+				endErr = <-sub.Err()
+				return
 			}
+		case <-sub.Done():
+			endErr = <-sub.Err()
+			return
 		}
-	}()
+	}
+}
 
-	return nil
+func decodeNotification[T any](data []byte) (T, error) {
+	var result T
+	var msg notification
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return result, err
+	}
+	if msg.Error != nil {
+		return result, msg.Error
+	}
+	err := json.Unmarshal(msg.Params.Result, &result)
+	return result, err
+}
+
+// logSubscriptionError is the SubscriptionErrorHandler used by default, it logs the error and skips the notification.
+func logSubscriptionError(logger *slog.Logger) SubscriptionErrorHandler {
+	return func(err *SubscriptionError) SubscriptionAction {
+		logger.Warn("sui subscription error", "method", err.Method, "error", err.Err)
+		return SkipMessage
+	}
 }
@@ -0,0 +1,131 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+func TestSubscriptionErrors(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+
+	actions := make(chan SubscriptionAction, 3)
+	actions <- SkipMessage
+	actions <- RetrySubscription
+	actions <- StopSubscription
+	ws, err := DialSuiWebsocketClient(ctx, srv.WsURL, WithSubscriptionErrorHandler(func(err *SubscriptionError) SubscriptionAction {
+		return <-actions
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	events := make(chan models.SuiEventResponse, 4)
+	sub, err := ws.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{
+		SuiEventFilter: map[string]interface{}{"All": []string{}},
+	}, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive := func() {
+		t.Helper()
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+		}
+	}
+	waitSubscription := func(id int64) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for subs := srv.Subscriptions(); len(subs) != 1 || subs[0].ID == id; subs = srv.Subscriptions() {
+			if time.Now().After(deadline) {
+				t.Fatal("the subscription was not issued again")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// a malformed notification is skipped
+	srv.Notify("suix_subscribeEvent", "malformed")
+	srv.Notify("suix_subscribeEvent", models.SuiEventResponse{Sender: suitest.Address})
+	receive()
+
+	// then the subscription is issued again
+	id := sub.ID()
+	srv.Notify("suix_subscribeEvent", "malformed")
+	waitSubscription(id)
+	srv.Notify("suix_subscribeEvent", models.SuiEventResponse{Sender: suitest.Address})
+	receive()
+	if n := len(srv.CallsTo("suix_unsubscribeEvent")); n != 1 {
+		t.Errorf("expected the previous subscription to be cancelled, got %d calls", n)
+	}
+
+	// then it is stopped with the error
+	srv.Notify("suix_subscribeEvent", "malformed")
+	select {
+	case err := <-sub.Err():
+		var subErr *SubscriptionError
+		if !errors.As(err, &subErr) || subErr.Method != "suix_subscribeEvent" {
+			t.Errorf("expected a SubscriptionError, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription was not stopped")
+	}
+	<-sub.Done()
+
+	stats := sub.Stats()
+	if stats != (SubscriptionStats{Received: 5, Delivered: 2, Dropped: 3, Retries: 1}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestSubscriptionBackpressure(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	ws, err := DialSuiWebsocketClient(ctx, srv.WsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	// nobody receives the events of the first subscription
+	stalled, err := ws.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{
+		SuiEventFilter: map[string]interface{}{"All": []string{}},
+	}, make(chan models.SuiEventResponse), WithQueue(wsconn.QueueConfig{Backpressure: wsconn.DropNewest, Size: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	effects := make(chan models.SuiEffects, 1)
+	_, err = ws.SubscribeTransaction(ctx, models.SuiXSubscribeTransactionsRequest{
+		TransactionFilter: models.TransactionFilterByFromAddress{FromAddress: suitest.Address},
+	}, effects)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		srv.Notify("suix_subscribeEvent", models.SuiEventResponse{Sender: suitest.Address})
+	}
+	// the stalled subscription doesn't hold the connection back
+	srv.Notify("suix_subscribeTransaction", models.SuiEffects{})
+	select {
+	case <-effects:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the transaction subscription was held back")
+	}
+
+	stats := stalled.Stats()
+	if stats.Queue.Dropped == 0 || stats.Queue.Depth > 2 || stats.Delivered != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
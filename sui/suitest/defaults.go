@@ -0,0 +1,250 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package suitest
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+// Values used by the default responses.
+const (
+	Address           = "0x7d20dcdb2bca4f508ea9613994683eb4e76e9c4ed371169677c1be02aaf0b58e"
+	GasCoinObjectID   = "0x3b6a9b3e5a0d5d7f6f0cc2f2d3a2b7b5b8bff3f4e6a01e2f5c8a9d1c2b3a4f5e"
+	ObjectDigest      = "8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd"
+	TransactionDigest = "5G8NtDc6Ed7Ky9mPUKLdMEbVqt6qJVhYyH6L4n7oR2rK"
+	ChainIdentifier   = "4c78adac"
+	Epoch             = "420"
+	CheckpointSeq     = "25000000"
+	ReferenceGasPrice = "750"
+)
+
+const suiCoinType = "0x2::sui::SUI"
+
+const gasCostSummary = `{"computationCost":"750000","storageCost":"1976000","storageRebate":"978120","nonRefundableStorageFee":"9880"}`
+
+const coin = `{
+	"coinType":"0x2::sui::SUI",
+	"coinObjectId":"` + GasCoinObjectID + `",
+	"version":"41","digest":"` + ObjectDigest + `",
+	"balance":"1000000000",
+	"previousTransaction":"` + TransactionDigest + `"
+}`
+
+const checkpoint = `{
+	"epoch":"` + Epoch + `","sequenceNumber":"` + CheckpointSeq + `",
+	"digest":"4hcbCGUzE6hqfPMHXRvGoagMKbMEUdNgnyuGHwKZxEDs",
+	"networkTotalTransactions":"1500000000",
+	"previousDigest":"9FkDNxr4tx1dWoyZ4V8N2JpSBqUq9Zdg3f6i7ucmQ3nH",
+	"epochRollingGasCostSummary":` + gasCostSummary + `,
+	"timestampMs":"1700000000000",
+	"transactions":["` + TransactionDigest + `"],
+	"checkpointCommitments":[],
+	"validatorSignature":"gE7CnMbB6JZyx9rY6Tc7qQ=="
+}`
+
+const event = `{
+	"id":{"txDigest":"` + TransactionDigest + `","eventSeq":"0"},
+	"packageId":"0x2","transactionModule":"coin","sender":"` + Address + `",
+	"type":"0x2::coin::CoinEvent","parsedJson":{"amount":"1000"},"bcs":"2Ax","timestampMs":"1700000000000"
+}`
+
+const epoch = `{
+	"epoch":"` + Epoch + `","validators":[],"epochTotalTransactions":"0",
+	"firstCheckpointId":"24990000","epochStartTimestamp":"1699913600000","endOfEpochInfo":null
+}`
+
+const txnMetaData = `{
+	"gas":[{"objectId":"` + GasCoinObjectID + `","version":41,"digest":"` + ObjectDigest + `"}],
+	"inputObjects":[],
+	"txBytes":"AAACAAgA6HZIFwAAAAAg"
+}`
+
+const normalizedFunction = `{"visibility":"Public","isEntry":true,"typeParameters":[],"parameters":[],"return":[]}`
+
+const normalizedModule = `{
+	"fileFormatVersion":6,"address":"0x2","name":"coin","friends":[],
+	"structs":{},"exposedFunctions":{"value":` + normalizedFunction + `}
+}`
+
+func emptyPage() json.RawMessage {
+	return json.RawMessage(`{"data":[],"nextCursor":null,"hasNextPage":false}`)
+}
+
+func page(items ...string) json.RawMessage {
+	return json.RawMessage(`{"data":[` + strings.Join(items, ",") + `],"nextCursor":null,"hasNextPage":false}`)
+}
+
+// objectData is an owned Coin<SUI> object with the given id.
+func objectData(objectId string) string {
+	return `{
+		"objectId":"` + objectId + `","version":"41","digest":"` + ObjectDigest + `",
+		"type":"0x2::coin::Coin<0x2::sui::SUI>",
+		"owner":{"AddressOwner":"` + Address + `"},
+		"previousTransaction":"` + TransactionDigest + `",
+		"storageRebate":"988000",
+		"content":{"dataType":"moveObject","type":"0x2::coin::Coin<0x2::sui::SUI>","hasPublicTransfer":true,
+			"fields":{"balance":"1000000000","id":{"id":"` + objectId + `"}}}
+	}`
+}
+
+// transactionBlock is a successful SUI transfer with the given digest.
+func transactionBlock(digest string) string {
+	return `{
+		"digest":"` + digest + `",
+		"transaction":{"data":{"messageVersion":"v1","transaction":{"kind":"ProgrammableTransaction","inputs":[],"transactions":[]},
+			"sender":"` + Address + `","gasData":{"payment":[{"objectId":"` + GasCoinObjectID + `","version":41,"digest":"` + ObjectDigest + `"}],
+			"owner":"` + Address + `","price":"` + ReferenceGasPrice + `","budget":"10000000"}},"txSignatures":[]},
+		"effects":{"messageVersion":"v1","status":{"status":"success"},"executedEpoch":"` + Epoch + `",
+			"gasUsed":` + gasCostSummary + `,
+			"transactionDigest":"` + digest + `",
+			"mutated":[{"owner":{"AddressOwner":"` + Address + `"},"reference":{"objectId":"` + GasCoinObjectID + `","version":42,"digest":"` + ObjectDigest + `"}}],
+			"gasObject":{"owner":{"AddressOwner":"` + Address + `"},"reference":{"objectId":"` + GasCoinObjectID + `","version":42,"digest":"` + ObjectDigest + `"}},
+			"dependencies":[]},
+		"events":[],
+		"objectChanges":[],
+		"balanceChanges":[{"owner":{"AddressOwner":"` + Address + `"},"coinType":"` + suiCoinType + `","amount":"-1747880"}],
+		"timestampMs":"1700000000000",
+		"checkpoint":"` + CheckpointSeq + `"
+	}`
+}
+
+// stringParam returns the string argument at index i of params, or def.
+func stringParam(params json.RawMessage, i int, def string) string {
+	var args []interface{}
+	if err := json.Unmarshal(params, &args); err != nil || len(args) <= i {
+		return def
+	}
+	if s, ok := args[i].(string); ok {
+		return s
+	}
+	return def
+}
+
+func static(result string) Handler {
+	raw := json.RawMessage(result)
+	return func(json.RawMessage) (interface{}, error) {
+		return raw, nil
+	}
+}
+
+// defaultHandlers answer every method called by sui.ISuiAPI and sui.ISubscribeAPI.
+func defaultHandlers() map[string]Handler {
+	return map[string]Handler{
+		// coins
+		"suix_getBalance":     static(`{"coinType":"` + suiCoinType + `","coinObjectCount":1,"totalBalance":"1000000000","lockedBalance":{}}`),
+		"suix_getAllBalances": static(`[{"coinType":"` + suiCoinType + `","coinObjectCount":1,"totalBalance":"1000000000","lockedBalance":{}}]`),
+		"suix_getCoins":       static(string(page(coin))),
+		"suix_getAllCoins":    static(string(page(coin))),
+		"suix_getCoinMetadata": static(`{"id":"0x9258181f5ceac8dbffb7030890243caed69a9599d2886d957a9cb7656af3bdb3","decimals":9,
+			"name":"Sui","symbol":"SUI","description":"","iconUrl":null}`),
+		"suix_getTotalSupply": static(`{"value":"10000000000000000000"}`),
+
+		// events
+		"sui_getEvents":    static(`[` + event + `]`),
+		"suix_queryEvents": static(string(page(event))),
+
+		// move
+		"sui_getMoveFunctionArgTypes":           static(`[{"Object":"ByMutableReference"},"Pure"]`),
+		"sui_getNormalizedMoveModulesByPackage": static(`{"coin":` + normalizedModule + `}`),
+		"sui_getNormalizedMoveModule":           static(normalizedModule),
+		"sui_getNormalizedMoveStruct":           static(`{"abilities":{"abilities":["Store","Key"]},"typeParameters":[],"fields":[]}`),
+		"sui_getNormalizedMoveFunction":         static(normalizedFunction),
+		"suix_resolveNameServiceNames":          static(string(page(`"example.sui"`))),
+		"suix_resolveNameServiceAddress":        static(`"` + Address + `"`),
+
+		// objects
+		"sui_getObject": func(params json.RawMessage) (interface{}, error) {
+			return json.RawMessage(`{"data":` + objectData(stringParam(params, 0, GasCoinObjectID)) + `}`), nil
+		},
+		"sui_multiGetObjects": func(params json.RawMessage) (interface{}, error) {
+			var args []json.RawMessage
+			var ids []string
+			if err := json.Unmarshal(params, &args); err == nil && len(args) > 0 {
+				_ = json.Unmarshal(args[0], &ids)
+			}
+			objects := make([]string, 0, len(ids))
+			for _, id := range ids {
+				objects = append(objects, `{"data":`+objectData(id)+`}`)
+			}
+			return json.RawMessage(`[` + strings.Join(objects, ",") + `]`), nil
+		},
+		"suix_getOwnedObjects":       static(string(page(`{"data":` + objectData(GasCoinObjectID) + `}`))),
+		"suix_getDynamicFields":      static(string(emptyPage())),
+		"suix_getDynamicFieldObject": static(`{"data":` + objectData(GasCoinObjectID) + `}`),
+		"sui_tryGetPastObject": func(params json.RawMessage) (interface{}, error) {
+			return json.RawMessage(`{"status":"VersionFound","details":` + objectData(stringParam(params, 0, GasCoinObjectID)) + `}`), nil
+		},
+		"sui_getLoadedChildObjects": static(`{"loadedChildObjects":[]}`),
+
+		// system
+		"sui_getCheckpoint":                     static(checkpoint),
+		"sui_getCheckpoints":                    static(string(page(checkpoint))),
+		"sui_getLatestCheckpointSequenceNumber": static(`"` + CheckpointSeq + `"`),
+		"suix_getReferenceGasPrice":             static(`"` + ReferenceGasPrice + `"`),
+		"suix_getCommitteeInfo":                 static(`{"epoch":"` + Epoch + `","validators":[["jc/20VUECmVvqfkW8dWvyNqkWEY=","2500"]]}`),
+		"suix_getStakes":                        static(`[]`),
+		"suix_getStakesByIds":                   static(`[]`),
+		"suix_getEpochs":                        static(string(page(epoch))),
+		"suix_getCurrentEpoch":                  static(epoch),
+		"suix_getLatestSuiSystemState": static(`{"epoch":"` + Epoch + `","protocolVersion":"50","systemStateVersion":"2",
+			"referenceGasPrice":"` + ReferenceGasPrice + `","safeMode":false,"epochStartTimestampMs":"1699913600000",
+			"epochDurationMs":"86400000","totalStake":"8000000000000000000","activeValidators":[],
+			"pendingRemovals":[],"atRiskValidators":[],"validatorReportRecords":[]}`),
+		"sui_getChainIdentifier": static(`"` + ChainIdentifier + `"`),
+		"suix_getValidatorsApy":  static(`{"apys":[],"epoch":"` + Epoch + `"}`),
+
+		// transactions
+		"sui_getTotalTransactionBlocks": static(`"1500000000"`),
+		"sui_getTransactionBlock": func(params json.RawMessage) (interface{}, error) {
+			return json.RawMessage(transactionBlock(stringParam(params, 0, TransactionDigest))), nil
+		},
+		"sui_multiGetTransactionBlocks": func(params json.RawMessage) (interface{}, error) {
+			var args []json.RawMessage
+			var digests []string
+			if err := json.Unmarshal(params, &args); err == nil && len(args) > 0 {
+				_ = json.Unmarshal(args[0], &digests)
+			}
+			blocks := make([]string, 0, len(digests))
+			for _, digest := range digests {
+				blocks = append(blocks, transactionBlock(digest))
+			}
+			return json.RawMessage(`[` + strings.Join(blocks, ",") + `]`), nil
+		},
+		"suix_queryTransactionBlocks": static(string(page(transactionBlock(TransactionDigest)))),
+		"sui_dryRunTransactionBlock":  static(transactionBlock(TransactionDigest)),
+		"sui_devInspectTransactionBlock": static(`{"effects":{"messageVersion":"v1","status":{"status":"success"},
+			"executedEpoch":"` + Epoch + `","gasUsed":` + gasCostSummary + `,"transactionDigest":"` + TransactionDigest + `",
+			"dependencies":[]},"events":[],"results":[]}`),
+		"sui_executeTransactionBlock": func(params json.RawMessage) (interface{}, error) {
+			// answer the digest of the submitted transaction, as a node would
+			digest := TransactionDigest
+			if d, err := models.ComputeTransactionDigest(stringParam(params, 0, "")); err == nil {
+				digest = string(d)
+			}
+			return json.RawMessage(transactionBlock(digest)), nil
+		},
+
+		// transaction builders
+		"unsafe_moveCall":             static(txnMetaData),
+		"unsafe_mergeCoins":           static(txnMetaData),
+		"unsafe_splitCoin":            static(txnMetaData),
+		"unsafe_splitCoinEqual":       static(txnMetaData),
+		"unsafe_publish":              static(txnMetaData),
+		"unsafe_transferObject":       static(txnMetaData),
+		"unsafe_transferSui":          static(txnMetaData),
+		"unsafe_pay":                  static(txnMetaData),
+		"unsafe_paySui":               static(txnMetaData),
+		"unsafe_payAllSui":            static(txnMetaData),
+		"unsafe_requestAddStake":      static(txnMetaData),
+		"unsafe_requestWithdrawStake": static(txnMetaData),
+		"unsafe_batchTransaction":     static(txnMetaData),
+
+		// subscriptions, the result is replaced by the subscription id
+		"suix_subscribeEvent":       static(`null`),
+		"suix_subscribeTransaction": static(`null`),
+	}
+}
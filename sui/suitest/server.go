@@ -0,0 +1,287 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package suitest provides an in-process Sui JSON-RPC server for testing code built on
+// sui.NewSuiClient and sui.NewSuiWebsocketClient.
+//
+// Every method called by sui.ISuiAPI answers a realistic default response out of the box, tests program
+// the methods they care about, inject errors or latency, and inspect the calls the client made:
+//
+//	srv := suitest.NewServer()
+//	defer srv.Close()
+//	srv.SetResult("suix_getReferenceGasPrice", "750")
+//	cli := sui.NewSuiClient(srv.URL)
+package suitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/yasir7ca/sui-go-sdk/common/sui_error"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+// Transports a Call can be received on.
+const (
+	TransportHTTP      = "http"
+	TransportWebsocket = "websocket"
+)
+
+// Handler answers a call, params is the JSON array of the call's arguments. A returned *models.JsonRPCError
+// is sent as is, any other error is sent with the code -32000.
+type Handler func(params json.RawMessage) (result interface{}, err error)
+
+// Call is a call received by the server.
+type Call struct {
+	Transport string
+	Method    string
+	Params    json.RawMessage
+	Time      time.Time
+}
+
+// Server is a Sui JSON-RPC server listening on a local port. It serves HTTP calls on URL and websocket
+// calls and subscriptions on WsURL.
+type Server struct {
+	// URL is the rpc url of the server, to be passed to sui.NewSuiClient.
+	URL string
+	// WsURL is the websocket url of the server, to be passed to sui.NewSuiWebsocketClient.
+	WsURL string
+
+	srv      *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu            sync.Mutex
+	handlers      map[string]Handler
+	latency       map[string]time.Duration
+	httpFailures  map[string][]int
+	calls         []Call
+	subscriptions map[int64]*subscription
+	nextID        int64
+}
+
+// anyMethod selects every method in SetLatency and FailHTTP.
+const anyMethod = ""
+
+// NewServer starts a server answering a default response to every method called by sui.ISuiAPI.
+// It must be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		handlers:      make(map[string]Handler),
+		latency:       make(map[string]time.Duration),
+		httpFailures:  make(map[string][]int),
+		subscriptions: make(map[int64]*subscription),
+	}
+	for method, h := range defaultHandlers() {
+		s.handlers[method] = h
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	s.WsURL = "ws" + strings.TrimPrefix(s.srv.URL, "http")
+	return s
+}
+
+// Close closes the websocket connections and shuts the server down.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for id, sub := range s.subscriptions {
+		sub.conn.close()
+		delete(s.subscriptions, id)
+	}
+	s.mu.Unlock()
+	s.srv.CloseClientConnections()
+	s.srv.Close()
+}
+
+// Handle programs the answer of a method.
+func (s *Server) Handle(method string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = h
+}
+
+// SetResult programs a method to answer result, which is marshaled to JSON unless it is a json.RawMessage.
+func (s *Server) SetResult(method string, result interface{}) {
+	s.Handle(method, func(json.RawMessage) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// SetError programs a method to fail with a JSON-RPC error.
+func (s *Server) SetError(method string, code int, message string) {
+	s.Handle(method, func(json.RawMessage) (interface{}, error) {
+		return nil, &models.JsonRPCError{Code: code, Message: message}
+	})
+}
+
+// SetLatency delays the answers of a method, an empty method delays every call.
+func (s *Server) SetLatency(method string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency[method] = d
+}
+
+// FailHTTP answers the next HTTP requests calling method with the given statuses, one status per request.
+// An empty method fails the next requests whatever they call.
+func (s *Server) FailHTTP(method string, statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.httpFailures[method] = append(s.httpFailures[method], statuses...)
+}
+
+// Calls returns the calls received so far, in order.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the calls of a method received so far, in order.
+func (s *Server) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range s.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls and the pending HTTP failures and latencies. Programmed answers are kept.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+	s.latency = make(map[string]time.Duration)
+	s.httpFailures = make(map[string][]int)
+}
+
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	Version string               `json:"jsonrpc"`
+	ID      json.RawMessage      `json:"id"`
+	Result  interface{}          `json:"result,omitempty"`
+	Error   *models.JsonRPCError `json:"error,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebsocket(w, r)
+		return
+	}
+
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	trimmed := bytes.TrimSpace(body.Bytes())
+	batch := len(trimmed) > 0 && trimmed[0] == '['
+
+	var reqs []request
+	var err error
+	if batch {
+		err = json.Unmarshal(trimmed, &reqs)
+	} else {
+		var req request
+		err = json.Unmarshal(trimmed, &req)
+		reqs = append(reqs, req)
+	}
+	if err != nil {
+		writeJSON(w, response{Version: "2.0", ID: json.RawMessage("null"), Error: &models.JsonRPCError{
+			Code:    sui_error.CodeParseError,
+			Message: err.Error(),
+		}})
+		return
+	}
+
+	if status, ok := s.httpFailure(reqs); ok {
+		w.WriteHeader(status)
+		return
+	}
+
+	rsps := make([]response, 0, len(reqs))
+	for _, req := range reqs {
+		rsps = append(rsps, s.call(TransportHTTP, req))
+	}
+	if batch {
+		writeJSON(w, rsps)
+	} else {
+		writeJSON(w, rsps[0])
+	}
+}
+
+// httpFailure pops the next injected HTTP failure matching the calls of a request.
+func (s *Server) httpFailure(reqs []request) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	methods := []string{anyMethod}
+	for _, req := range reqs {
+		methods = append(methods, req.Method)
+	}
+	for _, method := range methods {
+		if statuses := s.httpFailures[method]; len(statuses) > 0 {
+			s.httpFailures[method] = statuses[1:]
+			return statuses[0], true
+		}
+	}
+	return 0, false
+}
+
+// call records a call, waits for its injected latency and runs its handler.
+func (s *Server) call(transport string, req request) response {
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Transport: transport, Method: req.Method, Params: req.Params, Time: time.Now()})
+	h := s.handlers[req.Method]
+	delay := s.latency[anyMethod] + s.latency[req.Method]
+	s.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	rsp := response{Version: "2.0", ID: req.ID}
+	if h == nil {
+		rsp.Error = &models.JsonRPCError{
+			Code:    sui_error.CodeMethodNotFound,
+			Message: fmt.Sprintf("Method not found: %s", req.Method),
+		}
+		return rsp
+	}
+	params := req.Params
+	if len(params) == 0 {
+		params = json.RawMessage("[]")
+	}
+	result, err := h(params)
+	if err != nil {
+		rpcErr, ok := err.(*models.JsonRPCError)
+		if !ok {
+			rpcErr = &models.JsonRPCError{Code: sui_error.CodeServerError, Message: err.Error()}
+		}
+		rsp.Error = rpcErr
+		return rsp
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	rsp.Result = result
+	return rsp
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(v)
+}
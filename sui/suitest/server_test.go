@@ -0,0 +1,89 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package suitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/sui_error"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+func TestServer(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	cli := sui.NewSuiClient(srv.URL)
+	ctx := context.Background()
+
+	price, err := cli.SuiXGetReferenceGasPrice(ctx)
+	if err != nil || price != 750 {
+		t.Fatalf("unexpected gas price %d, %v", price, err)
+	}
+
+	objects, err := cli.SuiMultiGetObjects(ctx, models.SuiMultiGetObjectsRequest{ObjectIds: []string{"0x5", "0x6"}})
+	if err != nil || len(objects) != 2 || objects[1].Data.ObjectId != "0x6" {
+		t.Fatalf("unexpected objects %+v, %v", objects, err)
+	}
+
+	srv.SetError("sui_getObject", sui_error.CodeServerError, "Could not find the referenced object 0x5")
+	_, err = cli.SuiGetObject(ctx, models.SuiGetObjectRequest{ObjectId: "0x5"})
+	if !errors.Is(err, sui_error.ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+
+	// the default retry policy gets past the injected failures
+	srv.FailHTTP("suix_getReferenceGasPrice", http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	if _, err := cli.SuiXGetReferenceGasPrice(ctx); err != nil {
+		t.Errorf("expected the call to be retried, got %v", err)
+	}
+	if n := len(srv.CallsTo("suix_getReferenceGasPrice")); n != 2 {
+		t.Errorf("expected 2 answered calls, got %d", n)
+	}
+
+	srv.SetLatency("sui_getLatestCheckpointSequenceNumber", 200*time.Millisecond)
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := cli.SuiGetLatestCheckpointSequenceNumber(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the call to time out, got %v", err)
+	}
+}
+
+func TestServerSubscription(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	cli := sui.NewSuiWebsocketClient(srv.WsURL)
+
+	events := make(chan models.SuiEventResponse, 1)
+	sub, err := cli.SubscribeEvent(context.Background(), models.SuiXSubscribeEventsRequest{
+		SuiEventFilter: map[string]interface{}{"All": []string{}},
+	}, events)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := srv.Notify("suix_subscribeEvent", models.SuiEventResponse{Sender: suitest.Address}); n != 1 {
+		t.Fatalf("expected 1 subscription notified, got %d", n)
+	}
+	select {
+	case event := <-events:
+		if event.Sender != suitest.Address {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	<-sub.Done()
+	if n := len(srv.Subscriptions()); n != 0 {
+		t.Errorf("expected the subscription to be cancelled, %d left", n)
+	}
+}
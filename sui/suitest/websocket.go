@@ -0,0 +1,199 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package suitest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrUnknownSubscription = errors.New("suitest: unknown subscription")
+)
+
+// unsubscribeMethods maps the unsubscribe methods to the subscribe method they cancel.
+var unsubscribeMethods = map[string]string{
+	"suix_unsubscribeEvent":       "suix_subscribeEvent",
+	"suix_unsubscribeTransaction": "suix_subscribeTransaction",
+}
+
+type wsConn struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *wsConn) writeJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
+func (c *wsConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.conn.Close()
+}
+
+type subscription struct {
+	id     int64
+	method string
+	params json.RawMessage
+	conn   *wsConn
+}
+
+type notification struct {
+	Version string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  notificationParams `json:"params"`
+}
+
+type notificationParams struct {
+	Subscription int64       `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+// Subscription is an active subscription of a websocket client.
+type Subscription struct {
+	ID     int64
+	Method string
+	Params json.RawMessage
+}
+
+// Subscriptions returns the active subscriptions.
+func (s *Server) Subscriptions() []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subs := make([]Subscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		subs = append(subs, Subscription{ID: sub.id, Method: sub.method, Params: sub.params})
+	}
+	return subs
+}
+
+// Notify sends result to every active subscription made with the given subscribe method, e.g. `suix_subscribeEvent`,
+// and returns the number of subscriptions notified.
+func (s *Server) Notify(method string, result interface{}) int {
+	s.mu.Lock()
+	var subs []*subscription
+	for _, sub := range s.subscriptions {
+		if sub.method == method {
+			subs = append(subs, sub)
+		}
+	}
+	s.mu.Unlock()
+
+	notified := 0
+	for _, sub := range subs {
+		if s.notify(sub, result) == nil {
+			notified++
+		}
+	}
+	return notified
+}
+
+// NotifySubscription sends result to one subscription.
+func (s *Server) NotifySubscription(id int64, result interface{}) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[id]
+	s.mu.Unlock()
+	if !ok {
+		return ErrUnknownSubscription
+	}
+	return s.notify(sub, result)
+}
+
+// DropConnections closes every websocket connection, as a node restarting would.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := make(map[*wsConn]struct{})
+	for id, sub := range s.subscriptions {
+		conns[sub.conn] = struct{}{}
+		delete(s.subscriptions, id)
+	}
+	s.mu.Unlock()
+	for conn := range conns {
+		conn.close()
+	}
+}
+
+func (s *Server) notify(sub *subscription, result interface{}) error {
+	return sub.conn.writeJSON(notification{
+		Version: "2.0",
+		Method:  sub.method,
+		Params:  notificationParams{Subscription: sub.id, Result: result},
+	})
+}
+
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	c, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := &wsConn{conn: c}
+	defer func() {
+		s.mu.Lock()
+		for id, sub := range s.subscriptions {
+			if sub.conn == conn {
+				delete(s.subscriptions, id)
+			}
+		}
+		s.mu.Unlock()
+		conn.close()
+	}()
+
+	for {
+		_, data, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req request
+		if err := json.Unmarshal(data, &req); err != nil {
+			continue
+		}
+		// answer concurrently so that a slow call doesn't hold the others back
+		go func(req request) {
+			_ = conn.writeJSON(s.wsCall(conn, req))
+		}(req)
+	}
+}
+
+func (s *Server) wsCall(conn *wsConn, req request) response {
+	if strings.HasPrefix(req.Method, "suix_subscribe") {
+		rsp := s.call(TransportWebsocket, req)
+		if rsp.Error != nil {
+			return rsp
+		}
+		s.mu.Lock()
+		s.nextID++
+		id := s.nextID
+		s.subscriptions[id] = &subscription{id: id, method: req.Method, params: req.Params, conn: conn}
+		s.mu.Unlock()
+		rsp.Result = id
+		return rsp
+	}
+
+	if subscribeMethod, ok := unsubscribeMethods[req.Method]; ok {
+		s.mu.Lock()
+		s.calls = append(s.calls, Call{Transport: TransportWebsocket, Method: req.Method, Params: req.Params, Time: time.Now()})
+		var ids []int64
+		_ = json.Unmarshal(req.Params, &ids)
+		removed := false
+		if len(ids) == 1 {
+			if sub, ok := s.subscriptions[ids[0]]; ok && sub.conn == conn && sub.method == subscribeMethod {
+				delete(s.subscriptions, ids[0])
+				removed = true
+			}
+		}
+		s.mu.Unlock()
+		return response{Version: "2.0", ID: req.ID, Result: removed}
+	}
+
+	return s.call(TransportWebsocket, req)
+}
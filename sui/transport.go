@@ -0,0 +1,139 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
+)
+
+// Transport carries the JSON-RPC calls of a Client. It is implemented by *httpconn.HttpConn, and by the
+// websocket connection returned by NewWebsocketTransport.
+type Transport interface {
+	// CallContext performs a call and decodes its result into result.
+	CallContext(ctx context.Context, result interface{}, op httpconn.Operation) error
+	// BatchCallContext performs the calls of b, the errors of individual calls are set on their BatchElem.
+	BatchCallContext(ctx context.Context, b []httpconn.BatchElem) error
+	// RetryPolicy returns the policy retrying the calls that failed transiently, nil if they aren't retried.
+	RetryPolicy() *httpconn.RetryPolicy
+}
+
+var _ Transport = (*httpconn.HttpConn)(nil)
+
+// NewWebsocketTransport returns a Transport performing the calls on conn, so that a Client built with
+// NewSuiClientWithTransport shares the connection with the subscriptions. Calls failing transiently, the
+// connection lost before their response included, are retried under the retry policy configured by opts, see
+// WithRetryPolicy. `sui_executeTransactionBlock` is only resubmitted once its digest is not found on the node.
+func NewWebsocketTransport(conn *wsconn.WsConn, opts ...Option) Transport {
+	return newWebsocketTransport(conn, newClientOptions(opts))
+}
+
+func newWebsocketTransport(conn *wsconn.WsConn, options *clientOptions) *websocketTransport {
+	return &websocketTransport{conn: conn, policy: websocketRetryPolicy(options.http.RetryPolicy)}
+}
+
+// websocketTransport performs the calls of a Client on a websocket connection.
+type websocketTransport struct {
+	conn   *wsconn.WsConn
+	policy *httpconn.RetryPolicy
+}
+
+func (t *websocketTransport) CallContext(ctx context.Context, result interface{}, op httpconn.Operation) error {
+	return t.conn.CallWithRetry(ctx, result, wsconn.CallOp{Method: op.Method, Params: op.Params}, t.policy)
+}
+
+// BatchCallContext performs the calls of b concurrently on the connection, each one going through the
+// interceptors on its own. Only the closing of the connection or the end of ctx is reported as an error.
+func (t *websocketTransport) BatchCallContext(ctx context.Context, b []httpconn.BatchElem) error {
+	var wg sync.WaitGroup
+	for i := range b {
+		wg.Add(1)
+		go func(elem *httpconn.BatchElem) {
+			defer wg.Done()
+			elem.Error = t.CallContext(ctx, elem.Result, httpconn.Operation{Method: elem.Method, Params: elem.Params})
+		}(&b[i])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, elem := range b {
+		if errors.Is(elem.Error, wsconn.ErrClosed) {
+			return elem.Error
+		}
+	}
+	return nil
+}
+
+func (t *websocketTransport) RetryPolicy() *httpconn.RetryPolicy {
+	return t.policy
+}
+
+// websocketRetryPolicy extends policy to retry the calls whose connection was lost before their response.
+func websocketRetryPolicy(policy *httpconn.RetryPolicy) *httpconn.RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+	p := *policy
+	retryable := policy.Retryable
+	p.Retryable = func(err error) bool {
+		if errors.Is(err, wsconn.ErrConnectionLost) {
+			return true
+		}
+		if retryable != nil {
+			return retryable(err)
+		}
+		return httpconn.IsRetryableError(err)
+	}
+	return &p
+}
+
+// streamArray is httpconn.StreamArray on an HTTP transport. Other transports decode the whole result before
+// passing its elements to fn.
+func streamArray[T any](ctx context.Context, t Transport, op httpconn.Operation, field string, rest interface{}, fn func(T) error) error {
+	if h, ok := t.(*httpconn.HttpConn); ok {
+		return httpconn.StreamArray(ctx, h, op, field, rest, fn)
+	}
+
+	var raw json.RawMessage
+	if err := t.CallContext(ctx, &raw, op); err != nil {
+		return err
+	}
+	array := raw
+	if field != "" {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return err
+		}
+		array = fields[field]
+		if rest != nil {
+			// like httpconn.StreamArray, rest holds the other fields only
+			delete(fields, field)
+			others, err := json.Marshal(fields)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(others, rest); err != nil {
+				return err
+			}
+		}
+	}
+	var elems []T
+	if len(array) > 0 {
+		if err := json.Unmarshal(array, &elems); err != nil {
+			return err
+		}
+	}
+	for _, elem := range elems {
+		if err := fn(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
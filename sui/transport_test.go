@@ -0,0 +1,113 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+func TestWebsocketTransport(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	cli, err := DialSuiWebsocketClient(ctx, srv.WsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	price, err := cli.SuiXGetReferenceGasPrice(ctx)
+	if err != nil || price != 750 {
+		t.Fatalf("unexpected gas price %d, %v", price, err)
+	}
+
+	var checkpoints []models.CheckpointResponse
+	page, err := cli.SuiGetCheckpointsStream(ctx, models.SuiGetCheckpointsRequest{Limit: 1}, func(checkpoint models.CheckpointResponse) error {
+		checkpoints = append(checkpoints, checkpoint)
+		return nil
+	})
+	if err != nil || len(checkpoints) != 1 || checkpoints[0].SequenceNumber != suitest.CheckpointSeq {
+		t.Fatalf("unexpected checkpoints %+v, %v", checkpoints, err)
+	}
+	if len(page.Data) != 0 {
+		t.Errorf("expected the page to hold no data, got %d checkpoints", len(page.Data))
+	}
+
+	batch := cli.NewBatch()
+	balance := batch.SuiXGetBalance(models.SuiXGetBalanceRequest{Owner: suitest.Address, CoinType: "0x2::sui::SUI"})
+	chain := AddBatchCall[string](batch, "sui_getChainIdentifier")
+	gasPrice := batch.SuiXGetReferenceGasPrice()
+	if err := batch.Send(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if price, err := gasPrice.Result(); err != nil || price != 750 {
+		t.Errorf("unexpected gas price %d, %v", price, err)
+	}
+	if _, err := balance.Result(); err != nil {
+		t.Error(err)
+	}
+	if _, err := chain.Result(); err != nil {
+		t.Error(err)
+	}
+
+	// subscriptions share the connection
+	msgCh := make(chan models.SuiEventResponse, 1)
+	sub, err := cli.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{SuiEventFilter: map[string]interface{}{"All": []string{}}}, msgCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	for _, call := range srv.Calls() {
+		if call.Transport != suitest.TransportWebsocket {
+			t.Errorf("%s was called over %s", call.Method, call.Transport)
+		}
+	}
+}
+
+func TestWebsocketTransportRetry(t *testing.T) {
+	srv := suitest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	var intercepted atomic.Int32
+	cli, err := DialSuiWebsocketClient(ctx, srv.WsURL,
+		WithRetryPolicy(&httpconn.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		WithInterceptors(func(ctx context.Context, call *interceptor.Call, next interceptor.Invoker) error {
+			if call.Method == "suix_getReferenceGasPrice" {
+				intercepted.Add(1)
+			}
+			return next(ctx, call)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	// the node drops the connections holding a subscription
+	sub, err := cli.SubscribeEvent(ctx, models.SuiXSubscribeEventsRequest{SuiEventFilter: map[string]interface{}{"All": []string{}}}, make(chan models.SuiEventResponse, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	srv.SetLatency("suix_getReferenceGasPrice", 100*time.Millisecond)
+	time.AfterFunc(30*time.Millisecond, srv.DropConnections)
+	if price, err := cli.SuiXGetReferenceGasPrice(ctx); err != nil || price != 750 {
+		t.Fatalf("unexpected gas price %d, %v", price, err)
+	}
+	// the attempts run inside the interceptors, as over HTTP
+	if n := len(srv.CallsTo("suix_getReferenceGasPrice")); n < 2 {
+		t.Errorf("expected the call lost with the connection to be sent again, got %d calls", n)
+	}
+	if n := intercepted.Load(); n != 1 {
+		t.Errorf("expected the interceptors to see a single call, got %d", n)
+	}
+}
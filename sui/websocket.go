@@ -4,25 +4,71 @@
 package sui
 
 import (
+	"context"
+
 	"github.com/yasir7ca/sui-go-sdk/common/wsconn"
 )
 
 // ISuiWebsocketAPI defines the subscription API related interface, and then implement it by the WebsocketClient.
+// The methods of ISuiAPI are performed on the same connection as the subscriptions.
 type ISuiWebsocketAPI interface {
+	ISuiAPI
 	ISubscribeAPI
+	State() wsconn.ConnState
+	Health() wsconn.Health
+	Close() error
 }
 
 // WebsocketClient implements SuiWebsocketAPI related interfaces.
 type WebsocketClient struct {
+	ISuiAPI
 	ISubscribeAPI
+	conn *wsconn.WsConn
 }
 
 // NewSuiWebsocketClient instantiates the WebsocketClient to call the methods of each module.
-func NewSuiWebsocketClient(rpcUrl string) ISuiWebsocketAPI {
-	conn := wsconn.NewWsConn(rpcUrl)
+// The websocket connection is configured by opts, e.g. WithHeader, WithLogger or WithInterceptors.
+// A lost connection is dialed again and its subscriptions are issued again, see WithReconnectPolicy.
+// Every method of ISuiAPI is available too, performed on the same connection.
+func NewSuiWebsocketClient(rpcUrl string, opts ...Option) ISuiWebsocketAPI {
+	options := newClientOptions(opts)
+	return newWebsocketClient(wsconn.NewWsConnWithConfig(rpcUrl, options.ws), options)
+}
+
+// DialSuiWebsocketClient instantiates the WebsocketClient like NewSuiWebsocketClient, but returns the error of
+// the first dial instead of dialing again in the background.
+func DialSuiWebsocketClient(ctx context.Context, rpcUrl string, opts ...Option) (ISuiWebsocketAPI, error) {
+	options := newClientOptions(opts)
+	conn, err := wsconn.Dial(ctx, rpcUrl, options.ws)
+	if err != nil {
+		return nil, err
+	}
+	return newWebsocketClient(conn, options), nil
+}
+
+func newWebsocketClient(conn *wsconn.WsConn, options *clientOptions) *WebsocketClient {
 	return &WebsocketClient{
+		ISuiAPI: newClient(newWebsocketTransport(conn, options), options),
 		ISubscribeAPI: &suiSubscribeImpl{
-			conn: conn,
+			conn:    conn,
+			options: options,
 		},
+		conn: conn,
 	}
 }
+
+// State returns the state of the websocket connection.
+func (c *WebsocketClient) State() wsconn.ConnState {
+	return c.conn.State()
+}
+
+// Health returns the health of the websocket connection: the latency of the last ping, the time since the last
+// message and the number of reconnections.
+func (c *WebsocketClient) Health() wsconn.Health {
+	return c.conn.Health()
+}
+
+// Close closes the websocket connection, the subscriptions stop receiving notifications.
+func (c *WebsocketClient) Close() error {
+	return c.conn.Close()
+}
@@ -30,186 +30,193 @@ type IWriteTransactionAPI interface {
 }
 
 type suiWriteTransactionImpl struct {
-	conn *httpconn.HttpConn
+	conn         Transport
+	options      *clientOptions
+	gasEstimator *GasEstimator
 }
 
 // SuiExecuteTransactionBlock implements the method `sui_executeTransactionBlock`, executes a transaction using the transaction data and signature(s).
+// A transient failure is only retried after looking the transaction digest up, so a transaction is never submitted twice once it reached the chain.
 func (s *suiWriteTransactionImpl) SuiExecuteTransactionBlock(ctx context.Context, req models.SuiExecuteTransactionBlockRequest) (models.SuiTransactionBlockResponse, error) {
-	var rsp models.SuiTransactionBlockResponse
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "sui_executeTransactionBlock",
-		Params: []interface{}{
-			req.TxBytes,
-			req.Signature,
-			req.Options,
-			req.RequestType,
-		},
-	})
-	if err != nil {
-		return rsp, err
+	return s.executeTransactionBlock(ctx, req.TxBytes, req.Signature, req.Options, req.RequestType)
+}
+
+// executeTransactionBlock calls `sui_executeTransactionBlock` under the connection's retry policy.
+// Before resubmitting, it queries the digest of the transaction and returns the executed transaction if the node knows it.
+func (s *suiWriteTransactionImpl) executeTransactionBlock(ctx context.Context, txBytes string, signatures []string, options models.SuiTransactionBlockOptions, requestType string) (models.SuiTransactionBlockResponse, error) {
+	options = s.options.transactionOptions(options)
+	policy := s.conn.RetryPolicy()
+	for attempt := 1; ; attempt++ {
+		var rsp models.SuiTransactionBlockResponse
+		err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
+			Method: "sui_executeTransactionBlock",
+			Params: []interface{}{
+				txBytes,
+				signatures,
+				options,
+				requestType,
+			},
+		})
+		if err == nil {
+			return rsp, nil
+		}
+		if !policy.CanRetry(attempt, err) {
+			return rsp, err
+		}
+		digest, digestErr := models.ComputeTransactionDigest(txBytes)
+		if digestErr != nil {
+			return rsp, err
+		}
+		if waitErr := policy.Wait(ctx, attempt, err); waitErr != nil {
+			return rsp, err
+		}
+
+		var executed models.SuiTransactionBlockResponse
+		lookupErr := s.conn.CallContext(ctx, &executed, httpconn.Operation{
+			Method: "sui_getTransactionBlock",
+			Params: []interface{}{
+				digest,
+				options,
+			},
+		})
+		if lookupErr == nil && executed.Digest == string(digest) {
+			return executed, nil
+		}
 	}
-	return rsp, nil
 }
 
 // MoveCall implements the method `unsafe_moveCall`, creates an unsigned transaction to execute a Move call on the network, by calling the specified function in the module of a given package.
 func (s *suiWriteTransactionImpl) MoveCall(ctx context.Context, req models.MoveCallRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_moveCall",
-		Params: []interface{}{
-			req.Signer,
-			req.PackageObjectId,
-			req.Module,
-			req.Function,
-			req.TypeArguments,
-			req.Arguments,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_moveCall",
+			Params: []interface{}{
+				req.Signer,
+				req.PackageObjectId,
+				req.Module,
+				req.Function,
+				req.TypeArguments,
+				req.Arguments,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-	return rsp, nil
 }
 
 // MergeCoins implements the method `unsafe_mergeCoins`, creates an unsigned transaction to merge multiple coins into one coin.
 func (s *suiWriteTransactionImpl) MergeCoins(ctx context.Context, req models.MergeCoinsRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_mergeCoins",
-		Params: []interface{}{
-			req.Signer,
-			req.PrimaryCoin,
-			req.CoinToMerge,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_mergeCoins",
+			Params: []interface{}{
+				req.Signer,
+				req.PrimaryCoin,
+				req.CoinToMerge,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-	return rsp, nil
 }
 
 // SplitCoin implements the method `unsafe_splitCoin`, creates an unsigned transaction to split a coin object into multiple coins.
 func (s *suiWriteTransactionImpl) SplitCoin(ctx context.Context, req models.SplitCoinRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_splitCoin",
-		Params: []interface{}{
-			req.Signer,
-			req.CoinObjectId,
-			req.SplitAmounts,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_splitCoin",
+			Params: []interface{}{
+				req.Signer,
+				req.CoinObjectId,
+				req.SplitAmounts,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-	return rsp, nil
 }
 
 // SplitCoinEqual implements the method `unsafe_splitCoinEqual`, creates an unsigned transaction to split a coin object into multiple equal-size coins.
 func (s *suiWriteTransactionImpl) SplitCoinEqual(ctx context.Context, req models.SplitCoinEqualRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_splitCoinEqual",
-		Params: []interface{}{
-			req.Signer,
-			req.CoinObjectId,
-			req.SplitCount,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_splitCoinEqual",
+			Params: []interface{}{
+				req.Signer,
+				req.CoinObjectId,
+				req.SplitCount,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-	return rsp, nil
 }
 
 // Publish implements the method `unsafe_publish`, creates an unsigned transaction to publish a Move package.
 func (s *suiWriteTransactionImpl) Publish(ctx context.Context, req models.PublishRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_publish",
-		Params: []interface{}{
-			req.Sender,
-			req.CompiledModules,
-			req.Dependencies,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_publish",
+			Params: []interface{}{
+				req.Sender,
+				req.CompiledModules,
+				req.Dependencies,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-	return rsp, nil
 }
 
 // TransferObject implements the method `unsafe_transferObject`, creates an unsigned transaction to transfer an object from one address to another. The object's type must allow public transfers.
 func (s *suiWriteTransactionImpl) TransferObject(ctx context.Context, req models.TransferObjectRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_transferObject",
-		Params: []interface{}{
-			req.Signer,
-			req.ObjectId,
-			req.Gas,
-			req.GasBudget,
-			req.Recipient,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_transferObject",
+			Params: []interface{}{
+				req.Signer,
+				req.ObjectId,
+				req.Gas,
+				gasBudget,
+				req.Recipient,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
 }
 
 // TransferSui implements the method `unsafe_transferSui`, creates an unsigned transaction to send SUI coin object to a Sui address. The SUI object is also used as the gas object.
 func (s *suiWriteTransactionImpl) TransferSui(ctx context.Context, req models.TransferSuiRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_transferSui",
-		Params: []interface{}{
-			req.Signer,
-			req.SuiObjectId,
-			req.GasBudget,
-			req.Recipient,
-			req.Amount,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_transferSui",
+			Params: []interface{}{
+				req.Signer,
+				req.SuiObjectId,
+				gasBudget,
+				req.Recipient,
+				req.Amount,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
 }
 
 // Pay implements the method `unsafe_pay`, send `Coin<T>` to a list of addresses, where `T` can be any coin type, following a list of amounts.
 // The object specified in the `gas` field will be used to pay the gas fee for the transaction.
 // The gas object can not appear in `input_coins`. If the gas object is not specified, the RPC server will auto-select one.
 func (s *suiWriteTransactionImpl) Pay(ctx context.Context, req models.PayRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_pay",
-		Params: []interface{}{
-			req.Signer,
-			req.SuiObjectId,
-			req.Recipient,
-			req.Amount,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_pay",
+			Params: []interface{}{
+				req.Signer,
+				req.SuiObjectId,
+				req.Recipient,
+				req.Amount,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
 }
 
 // PaySui implements the method `unsafe_paySui`, send SUI coins to a list of addresses, following a list of amounts.
@@ -220,22 +227,18 @@ func (s *suiWriteTransactionImpl) Pay(ctx context.Context, req models.PayRequest
 // 3. the balance of the first input coin after tx is sum(input_coins) - sum(amounts) - actual_gas_cost
 // 4. all other input coints other than the first one are deleted.
 func (s *suiWriteTransactionImpl) PaySui(ctx context.Context, req models.PaySuiRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_paySui",
-		Params: []interface{}{
-			req.Signer,
-			req.SuiObjectId,
-			req.Recipient,
-			req.Amount,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_paySui",
+			Params: []interface{}{
+				req.Signer,
+				req.SuiObjectId,
+				req.Recipient,
+				req.Amount,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
 }
 
 // PayAllSui implements the method `unsafe_payAllSui`, send all SUI coins to one recipient.
@@ -246,99 +249,73 @@ func (s *suiWriteTransactionImpl) PaySui(ctx context.Context, req models.PaySuiR
 // 3. the balance of the first input coin after tx is sum(input_coins) - actual_gas_cost.
 // 4. all other input coins other than the first are deleted.
 func (s *suiWriteTransactionImpl) PayAllSui(ctx context.Context, req models.PayAllSuiRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_payAllSui",
-		Params: []interface{}{
-			req.Signer,
-			req.SuiObjectId,
-			req.Recipient,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_payAllSui",
+			Params: []interface{}{
+				req.Signer,
+				req.SuiObjectId,
+				req.Recipient,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
 }
 
 // RequestAddStake implements the method `unsafe_requestAddStake`, add stake to a validator's staking pool using multiple coins and amount.
 func (s *suiWriteTransactionImpl) RequestAddStake(ctx context.Context, req models.AddStakeRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_requestAddStake",
-		Params: []interface{}{
-			req.Signer,
-			req.Coins,
-			req.Amount,
-			req.Validator,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_requestAddStake",
+			Params: []interface{}{
+				req.Signer,
+				req.Coins,
+				req.Amount,
+				req.Validator,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
 }
 
 // RequestWithdrawStake implements the method `unsafe_requestWithdrawStake`, withdraw stake from a validator's staking pool.
 func (s *suiWriteTransactionImpl) RequestWithdrawStake(ctx context.Context, req models.WithdrawStakeRequest) (models.TxnMetaData, error) {
-	var rsp models.TxnMetaData
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_requestWithdrawStake",
-		Params: []interface{}{
-			req.Signer,
-			req.StakedObjectId,
-			req.Gas,
-			req.GasBudget,
-		},
+	return s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_requestWithdrawStake",
+			Params: []interface{}{
+				req.Signer,
+				req.StakedObjectId,
+				req.Gas,
+				gasBudget,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
 }
 
 // BatchTransaction implements the method `unsafe_batchTransaction`, creates an unsigned batched transaction.
 func (s *suiWriteTransactionImpl) BatchTransaction(ctx context.Context, req models.BatchTransactionRequest) (models.BatchTransactionResponse, error) {
-	var rsp models.BatchTransactionResponse
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "unsafe_batchTransaction",
-		Params: []interface{}{
-			req.Signer,
-			req.RPCTransactionRequestParams,
-			req.Gas,
-			req.GasBudget,
-			req.SuiTransactionBlockBuilderMode,
-		},
+	rsp, err := s.buildTransaction(ctx, req.GasBudget, func(gasBudget string) httpconn.Operation {
+		return httpconn.Operation{
+			Method: "unsafe_batchTransaction",
+			Params: []interface{}{
+				req.Signer,
+				req.RPCTransactionRequestParams,
+				req.Gas,
+				gasBudget,
+				req.SuiTransactionBlockBuilderMode,
+			},
+		}
 	})
-	if err != nil {
-		return rsp, err
-	}
-
-	return rsp, nil
+	return models.BatchTransactionResponse(rsp), err
 }
 
 // SignAndExecuteTransactionBlock sign a transaction block and submit to the Fullnode for execution.
 func (s *suiWriteTransactionImpl) SignAndExecuteTransactionBlock(ctx context.Context, req models.SignAndExecuteTransactionBlockRequest) (models.SuiTransactionBlockResponse, error) {
-	var rsp models.SuiTransactionBlockResponse
-
 	signedTxn := req.TxnMetaData.SignSerializedSigWith(req.PriKey)
 
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "sui_executeTransactionBlock",
-		Params: []interface{}{
-			signedTxn.TxBytes,
-			[]string{signedTxn.Signature},
-			req.Options,
-			req.RequestType,
-		},
-	})
-
+	rsp, err := s.executeTransactionBlock(ctx, signedTxn.TxBytes, []string{signedTxn.Signature}, req.Options, req.RequestType)
 	if err != nil {
 		return rsp, err
 	}
@@ -354,16 +331,7 @@ func (s *suiWriteTransactionImpl) SignAndExecuteTransactionBlockWithKMS(ctx cont
 		return rsp, nil // returnn proper error here
 	}
 
-	err := s.conn.CallContext(ctx, &rsp, httpconn.Operation{
-		Method: "sui_executeTransactionBlock",
-		Params: []interface{}{
-			req.TxnMetaData.TxBytes,
-			[]string{signature},
-			req.Options,
-			req.RequestType,
-		},
-	})
-
+	rsp, err := s.executeTransactionBlock(ctx, req.TxnMetaData.TxBytes, []string{signature}, req.Options, req.RequestType)
 	if err != nil {
 		return rsp, err
 	}
@@ -0,0 +1,89 @@
+// Copyright (c) BlockVision, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sui
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yasir7ca/sui-go-sdk/common/httpconn"
+	"github.com/yasir7ca/sui-go-sdk/common/interceptor"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/sui/suitest"
+)
+
+// executedDigest is the digest of the transaction of suitest, blake2b-256 of "TransactionData::" and its bytes in base58.
+const executedDigest = "6XjJEFsysQKXxoBie2riumWZg4CYe6tcs51sirm8Ckrn"
+
+func TestExecuteTransactionBlockRetry(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(srv *suitest.Server)
+		calls []string
+	}{
+		{
+			name: "digest found",
+			setup: func(srv *suitest.Server) {
+				srv.FailHTTP("sui_executeTransactionBlock", http.StatusServiceUnavailable)
+			},
+			calls: []string{"sui_executeTransactionBlock", "sui_getTransactionBlock"},
+		},
+		{
+			name: "digest missing",
+			setup: func(srv *suitest.Server) {
+				srv.FailHTTP("sui_executeTransactionBlock", http.StatusServiceUnavailable)
+				srv.SetError("sui_getTransactionBlock", -32602, "Could not find the referenced transaction")
+			},
+			calls: []string{"sui_executeTransactionBlock", "sui_getTransactionBlock", "sui_executeTransactionBlock"},
+		},
+		{
+			name: "lookup failed",
+			setup: func(srv *suitest.Server) {
+				srv.FailHTTP("sui_executeTransactionBlock", http.StatusServiceUnavailable)
+				srv.FailHTTP("sui_getTransactionBlock", http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+			},
+			calls: []string{"sui_executeTransactionBlock", "sui_getTransactionBlock", "sui_executeTransactionBlock"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := suitest.NewServer()
+			defer srv.Close()
+			tt.setup(srv)
+			var calls []string
+			var lookups []interface{}
+			cli := NewSuiClient(srv.URL,
+				WithRetryPolicy(&httpconn.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+				WithInterceptors(func(ctx context.Context, call *interceptor.Call, next interceptor.Invoker) error {
+					calls = append(calls, call.Method)
+					if call.Method == "sui_getTransactionBlock" {
+						lookups = append(lookups, call.Params[0])
+					}
+					return next(ctx, call)
+				}))
+
+			rsp, err := cli.SuiExecuteTransactionBlock(context.Background(), models.SuiExecuteTransactionBlockRequest{
+				TxBytes:   "AAACAAgA6HZIFwAAAAAg",
+				Signature: []string{"c2lnbmF0dXJl"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rsp.Digest != executedDigest {
+				t.Errorf("expected the transaction %s, got %s", executedDigest, rsp.Digest)
+			}
+			if !slices.Equal(calls, tt.calls) {
+				t.Errorf("expected the calls %v, got %v", tt.calls, calls)
+			}
+			for _, digest := range lookups {
+				if digest != models.TransactionDigest(executedDigest) {
+					t.Errorf("expected a lookup of %s, got %v", executedDigest, digest)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,468 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+	"github.com/yasir7ca/sui-go-sdk/models/sui_types"
+)
+
+var (
+	ErrMissingSender     = errors.New("transaction has no sender")
+	ErrMissingGasPayment = errors.New("transaction has no gas payment")
+	ErrMissingGasPrice   = errors.New("transaction has no gas price")
+	ErrMissingGasBudget  = errors.New("transaction has no gas budget")
+	ErrNoCommands        = errors.New("transaction has no commands")
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrTooManyArguments  = errors.New("transaction has more than 65535 inputs or commands")
+)
+
+// GasEstimator estimates the gas price and budget of the transactions built, see sui.NewGasEstimator.
+type GasEstimator interface {
+	// ReferenceGasPrice returns the gas price of the transactions of the epoch.
+	ReferenceGasPrice(ctx context.Context) (uint64, error)
+	// EstimateGasBudget returns the gas budget of data, whose budget is ignored.
+	EstimateGasBudget(ctx context.Context, data *TransactionData) (uint64, error)
+}
+
+// GasSelector selects the coins paying for the gas of the transactions built, see sui.NewGasCoinSelector.
+type GasSelector interface {
+	// SelectGas returns coins of owner covering budget, none of them in exclude. The budget includes the amounts
+	// split off the gas coin by the transaction.
+	SelectGas(ctx context.Context, owner bcs.SuiAddress, budget uint64, exclude []bcs.ObjectID) ([]bcs.ObjectRef, error)
+}
+
+// Builder builds a programmable transaction block locally, without the `unsafe_*` methods of the node. Each
+// command returns its result as an Argument for the next commands. The first error met is returned by Build.
+type Builder struct {
+	inputs   []CallArg
+	commands []Command
+	objects  map[bcs.ObjectID]uint16
+
+	sender     *bcs.SuiAddress
+	gasOwner   *bcs.SuiAddress
+	payment    []bcs.ObjectRef
+	gasPrice   uint64
+	gasBudget  uint64
+	expiration TransactionExpiration
+	estimator  GasEstimator
+	selector   GasSelector
+
+	err error
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		objects:    make(map[bcs.ObjectID]uint16),
+		expiration: TransactionExpiration{None: &struct{}{}},
+	}
+}
+
+func (b *Builder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Err returns the first error met building the transaction.
+func (b *Builder) Err() error {
+	return b.err
+}
+
+// SetSender sets the address signing the transaction.
+func (b *Builder) SetSender(sender bcs.SuiAddress) *Builder {
+	b.sender = &sender
+	return b
+}
+
+// SetGasOwner sets the owner of the gas coins when it isn't the sender, for sponsored transactions.
+func (b *Builder) SetGasOwner(owner bcs.SuiAddress) *Builder {
+	b.gasOwner = &owner
+	return b
+}
+
+// SetGasPayment sets the coins paying for the gas, they are merged into the gas coin.
+func (b *Builder) SetGasPayment(coins ...bcs.ObjectRef) *Builder {
+	b.payment = coins
+	return b
+}
+
+// SetGasPrice sets the gas price, at least the reference gas price of the epoch.
+func (b *Builder) SetGasPrice(price uint64) *Builder {
+	b.gasPrice = price
+	return b
+}
+
+// SetGasBudget sets the maximum amount of MIST the transaction can spend on gas.
+func (b *Builder) SetGasBudget(budget uint64) *Builder {
+	b.gasBudget = budget
+	return b
+}
+
+// SetGasEstimator estimates the gas price and budget left unset when the transaction is built.
+func (b *Builder) SetGasEstimator(estimator GasEstimator) *Builder {
+	b.estimator = estimator
+	return b
+}
+
+// SetGasSelector selects the gas payment when none is set, among the coins of the gas owner that aren't inputs of
+// the transaction.
+func (b *Builder) SetGasSelector(selector GasSelector) *Builder {
+	b.selector = selector
+	return b
+}
+
+// SetExpiration sets the last epoch the transaction can be executed in.
+func (b *Builder) SetExpiration(epoch uint64) *Builder {
+	b.expiration = TransactionExpiration{Epoch: &epoch}
+	return b
+}
+
+// Gas returns the gas coin, the coin the gas payment is merged into.
+func (b *Builder) Gas() Argument {
+	return Argument{GasCoin: &struct{}{}}
+}
+
+func (b *Builder) input(arg CallArg) Argument {
+	if len(b.inputs) > math.MaxUint16 {
+		b.setErr(ErrTooManyArguments)
+		return Argument{}
+	}
+	i := uint16(len(b.inputs))
+	b.inputs = append(b.inputs, arg)
+	return Argument{Input: &i}
+}
+
+// Pure adds the BCS encoding of v as an input: an integer of a sized type such as uint64, a bool, a string, a
+// bcs.SuiAddress or a slice of them.
+func (b *Builder) Pure(v interface{}) Argument {
+	value, err := bcs.Marshal(v)
+	if err != nil {
+		b.setErr(fmt.Errorf("pure input: %w", err))
+		return Argument{}
+	}
+	return b.PureBytes(value)
+}
+
+// PureBytes adds an input already BCS encoded.
+func (b *Builder) PureBytes(value []byte) Argument {
+	return b.input(CallArg{Pure: &value})
+}
+
+// PureAddress adds a hex address as an input.
+func (b *Builder) PureAddress(address string) Argument {
+	addr, err := bcs.ParseSuiAddress(address)
+	if err != nil {
+		b.setErr(err)
+		return Argument{}
+	}
+	return b.Pure(addr)
+}
+
+// Object adds an object as an input. An object is added once, when it is added again as a shared object the
+// input is mutable if either is.
+func (b *Builder) Object(arg ObjectArg) Argument {
+	id, ok := objectID(arg)
+	if !ok {
+		b.setErr(fmt.Errorf("%w: object input without a variant", ErrInvalidArgument))
+		return Argument{}
+	}
+	if i, ok := b.objects[id]; ok {
+		if prev := b.inputs[i].Object.SharedObject; prev != nil && arg.SharedObject != nil {
+			prev.Mutable = prev.Mutable || arg.SharedObject.Mutable
+		}
+		return Argument{Input: &i}
+	}
+	input := b.input(CallArg{Object: &arg})
+	if input.Input != nil {
+		b.objects[id] = *input.Input
+	}
+	return input
+}
+
+// OwnedObject adds an object owned by the sender, or an immutable object, as an input.
+func (b *Builder) OwnedObject(ref bcs.ObjectRef) Argument {
+	return b.Object(ObjectArg{ImmOrOwnedObject: &ref})
+}
+
+// SharedObject adds a shared object as an input, mutable if the commands modify it.
+func (b *Builder) SharedObject(id bcs.ObjectID, initialSharedVersion uint64, mutable bool) Argument {
+	return b.Object(ObjectArg{SharedObject: &SharedObject{ID: id, InitialSharedVersion: initialSharedVersion, Mutable: mutable}})
+}
+
+// ReceivingObject adds an object sent to another object as an input, for `transfer::receive`.
+func (b *Builder) ReceivingObject(ref bcs.ObjectRef) Argument {
+	return b.Object(ObjectArg{Receiving: &ref})
+}
+
+func objectID(arg ObjectArg) (bcs.ObjectID, bool) {
+	switch {
+	case arg.ImmOrOwnedObject != nil:
+		return arg.ImmOrOwnedObject.ObjectID, true
+	case arg.SharedObject != nil:
+		return arg.SharedObject.ID, true
+	case arg.Receiving != nil:
+		return arg.Receiving.ObjectID, true
+	default:
+		return bcs.ObjectID{}, false
+	}
+}
+
+func (b *Builder) command(cmd Command) Argument {
+	if len(b.commands) > math.MaxUint16 {
+		b.setErr(ErrTooManyArguments)
+		return Argument{}
+	}
+	i := uint16(len(b.commands))
+	b.commands = append(b.commands, cmd)
+	return Argument{Result: &i}
+}
+
+// SplitCoins splits amounts off coin, and returns the new coins.
+func (b *Builder) SplitCoins(coin Argument, amounts ...Argument) []Argument {
+	result := b.command(Command{SplitCoins: &SplitCoins{Coin: coin, Amounts: amounts}})
+	coins := make([]Argument, len(amounts))
+	for i := range amounts {
+		coins[i] = result.Nested(uint16(i))
+	}
+	return coins
+}
+
+// MergeCoins merges sources into destination.
+func (b *Builder) MergeCoins(destination Argument, sources ...Argument) {
+	b.command(Command{MergeCoins: &MergeCoins{Destination: destination, Sources: sources}})
+}
+
+// TransferObjects transfers objects to address.
+func (b *Builder) TransferObjects(objects []Argument, address Argument) {
+	b.command(Command{TransferObjects: &TransferObjects{Objects: objects, Address: address}})
+}
+
+// MoveCall calls a Move function, target is "package::module::function" and typeArguments are types such as
+// "0x2::sui::SUI". It returns the result of the call, Nested returns the results of a function returning several.
+func (b *Builder) MoveCall(target string, typeArguments []string, arguments ...Argument) Argument {
+	parts := strings.Split(target, "::")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		b.setErr(fmt.Errorf("invalid move call target %q", target))
+		return Argument{}
+	}
+	pkg, err := bcs.ParseSuiAddress(parts[0])
+	if err != nil {
+		b.setErr(err)
+		return Argument{}
+	}
+	call := &ProgrammableMoveCall{Package: pkg, Module: parts[1], Function: parts[2], Arguments: arguments}
+	for _, typ := range typeArguments {
+		tag, err := bcs.ParseTypeTag(typ)
+		if err != nil {
+			b.setErr(err)
+			return Argument{}
+		}
+		call.TypeArguments = append(call.TypeArguments, tag)
+	}
+	return b.command(Command{MoveCall: call})
+}
+
+// MakeMoveVec makes a vector of elements. typ is the type of the elements, it can be left empty unless the
+// elements are pure inputs or there are none.
+func (b *Builder) MakeMoveVec(typ string, elements ...Argument) Argument {
+	cmd := &MakeMoveVec{Elements: elements}
+	if typ != "" {
+		tag, err := bcs.ParseTypeTag(typ)
+		if err != nil {
+			b.setErr(err)
+			return Argument{}
+		}
+		cmd.Type = &tag
+	}
+	return b.command(Command{MakeMoveVec: cmd})
+}
+
+// Publish publishes the compiled modules of a package, and returns its UpgradeCap.
+func (b *Builder) Publish(modules [][]byte, dependencies []bcs.ObjectID) Argument {
+	return b.command(Command{Publish: &Publish{Modules: modules, Dependencies: dependencies}})
+}
+
+// Upgrade upgrades the package pkg with the ticket returned by `package::authorize_upgrade`, and returns the
+// UpgradeReceipt to pass to `package::commit_upgrade`.
+func (b *Builder) Upgrade(modules [][]byte, dependencies []bcs.ObjectID, pkg bcs.ObjectID, ticket Argument) Argument {
+	return b.command(Command{Upgrade: &Upgrade{Modules: modules, Dependencies: dependencies, Package: pkg, Ticket: ticket}})
+}
+
+// BuildKind returns the programmable transaction alone, without the sender and the gas data.
+func (b *Builder) BuildKind() (*TransactionKind, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.commands) == 0 {
+		return nil, ErrNoCommands
+	}
+	for i, cmd := range b.commands {
+		for _, arg := range commandArguments(cmd) {
+			if err := b.checkArgument(arg, i); err != nil {
+				return nil, fmt.Errorf("command %d: %w", i, err)
+			}
+		}
+	}
+	return &TransactionKind{ProgrammableTransaction: &ProgrammableTransaction{Inputs: b.inputs, Commands: b.commands}}, nil
+}
+
+// Build is BuildContext with the background context.
+func (b *Builder) Build() (*TransactionData, error) {
+	return b.BuildContext(context.Background())
+}
+
+// BuildContext returns the transaction data, the sender and the gas payment must be set. The gas price and budget
+// left unset are estimated by the GasEstimator of the builder, if any, and the gas payment left unset is selected
+// by its GasSelector once the budget is known, to cover it and the amounts split off the gas coin. The budget is then estimated without gas payment, which the dry run
+// of the node allows.
+func (b *Builder) BuildContext(ctx context.Context) (*TransactionData, error) {
+	kind, err := b.BuildKind()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case b.sender == nil:
+		return nil, ErrMissingSender
+	case len(b.payment) == 0 && b.selector == nil:
+		return nil, ErrMissingGasPayment
+	}
+	price := b.gasPrice
+	if price == 0 && b.estimator != nil {
+		if price, err = b.estimator.ReferenceGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("get gas price: %w", err)
+		}
+	}
+	if price == 0 {
+		return nil, ErrMissingGasPrice
+	}
+	owner := *b.sender
+	if b.gasOwner != nil {
+		owner = *b.gasOwner
+	}
+	data := &TransactionData{V1: &TransactionDataV1{
+		Kind:   *kind,
+		Sender: *b.sender,
+		GasData: GasData{
+			Payment: b.payment,
+			Owner:   owner,
+			Price:   price,
+			Budget:  b.gasBudget,
+		},
+		Expiration: b.expiration,
+	}}
+	if data.V1.GasData.Budget == 0 && b.estimator != nil {
+		if data.V1.GasData.Budget, err = b.estimator.EstimateGasBudget(ctx, data); err != nil {
+			return nil, fmt.Errorf("estimate gas budget: %w", err)
+		}
+	}
+	if data.V1.GasData.Budget == 0 {
+		return nil, ErrMissingGasBudget
+	}
+	if len(data.V1.GasData.Payment) == 0 {
+		exclude := make([]bcs.ObjectID, 0, len(b.objects))
+		for id := range b.objects {
+			exclude = append(exclude, id)
+		}
+		required := data.V1.GasData.Budget + gasSpent(kind.ProgrammableTransaction)
+		if required < data.V1.GasData.Budget {
+			required = math.MaxUint64
+		}
+		if data.V1.GasData.Payment, err = b.selector.SelectGas(ctx, owner, required, exclude); err != nil {
+			return nil, fmt.Errorf("select gas payment: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// BuildTxnMetaData is BuildTxnMetaDataContext with the background context.
+func (b *Builder) BuildTxnMetaData() (models.TxnMetaData, error) {
+	return b.BuildTxnMetaDataContext(context.Background())
+}
+
+// BuildTxnMetaDataContext returns the transaction built by BuildContext as the TxnMetaData of
+// SignAndExecuteTransactionBlock.
+func (b *Builder) BuildTxnMetaDataContext(ctx context.Context) (models.TxnMetaData, error) {
+	data, err := b.BuildContext(ctx)
+	if err != nil {
+		return models.TxnMetaData{}, err
+	}
+	txBytes, err := data.TxBytes()
+	if err != nil {
+		return models.TxnMetaData{}, err
+	}
+	payment := data.V1.GasData.Payment
+	gas := make([]sui_types.SuiObjectRef, len(payment))
+	for i, ref := range payment {
+		gas[i] = sui_types.SuiObjectRef{ObjectId: ref.ObjectID.String(), Version: ref.Version, Digest: ref.Digest.String()}
+	}
+	return models.TxnMetaData{Gas: gas, TxBytes: txBytes}, nil
+}
+
+// gasSpent returns the sum of the pure amounts split off the gas coin, at most math.MaxUint64.
+func gasSpent(ptb *ProgrammableTransaction) uint64 {
+	var spent uint64
+	for _, cmd := range ptb.Commands {
+		if cmd.SplitCoins == nil || cmd.SplitCoins.Coin.GasCoin == nil {
+			continue
+		}
+		for _, amount := range cmd.SplitCoins.Amounts {
+			if v, ok := pureU64(ptb, amount); ok {
+				if spent+v < spent {
+					return math.MaxUint64
+				}
+				spent += v
+			}
+		}
+	}
+	return spent
+}
+
+func commandArguments(cmd Command) []Argument {
+	switch {
+	case cmd.MoveCall != nil:
+		return cmd.MoveCall.Arguments
+	case cmd.TransferObjects != nil:
+		return append([]Argument{cmd.TransferObjects.Address}, cmd.TransferObjects.Objects...)
+	case cmd.SplitCoins != nil:
+		return append([]Argument{cmd.SplitCoins.Coin}, cmd.SplitCoins.Amounts...)
+	case cmd.MergeCoins != nil:
+		return append([]Argument{cmd.MergeCoins.Destination}, cmd.MergeCoins.Sources...)
+	case cmd.MakeMoveVec != nil:
+		return cmd.MakeMoveVec.Elements
+	case cmd.Upgrade != nil:
+		return []Argument{cmd.Upgrade.Ticket}
+	default:
+		return nil
+	}
+}
+
+// checkArgument checks arg references an input or the result of a command before the command cmd.
+func (b *Builder) checkArgument(arg Argument, cmd int) error {
+	switch {
+	case arg.GasCoin != nil:
+		return nil
+	case arg.Input != nil:
+		if int(*arg.Input) >= len(b.inputs) {
+			return fmt.Errorf("%w: input %d out of %d", ErrInvalidArgument, *arg.Input, len(b.inputs))
+		}
+		return nil
+	case arg.Result != nil:
+		if int(*arg.Result) >= cmd {
+			return fmt.Errorf("%w: result of command %d", ErrInvalidArgument, *arg.Result)
+		}
+		return nil
+	case arg.NestedResult != nil:
+		if int(arg.NestedResult.Result) >= cmd {
+			return fmt.Errorf("%w: result of command %d", ErrInvalidArgument, arg.NestedResult.Result)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: argument not set", ErrInvalidArgument)
+	}
+}
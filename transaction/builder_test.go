@@ -0,0 +1,166 @@
+package transaction
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+const gasDigest = "8D1jFkVtn2qXyRtcVEbvQxHQ8w4Dhxt3wrp1yQ8X9uPd"
+
+func address(hex string) string {
+	return strings.Repeat("00", 31) + hex
+}
+
+func newBuilder(t *testing.T) *Builder {
+	gas, err := bcs.ParseObjectRef("0x7", 3, gasDigest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewBuilder().
+		SetSender(bcs.MustParseSuiAddress("0x5")).
+		SetGasPayment(gas).
+		SetGasPrice(1000).
+		SetGasBudget(5000000)
+}
+
+func TestBuildTransferSui(t *testing.T) {
+	b := newBuilder(t)
+	coins := b.SplitCoins(b.Gas(), b.Pure(uint64(100)))
+	b.TransferObjects(coins, b.PureAddress("0x9"))
+	data, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := data.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest, _ := bcs.ParseDigest(gasDigest)
+	want, _ := hex.DecodeString("" +
+		"00" + "00" + // TransactionData::V1, TransactionKind::ProgrammableTransaction
+		"02" + "00" + "08" + "6400000000000000" + "00" + "20" + address("09") + // the pure inputs
+		"02" + "02" + "00" + "01" + "01" + "0000" + // SplitCoins(GasCoin, [Input(0)])
+		"01" + "01" + "03" + "0000" + "0000" + "01" + "0100" + // TransferObjects([NestedResult(0, 0)], Input(1))
+		address("05") + // sender
+		"01" + address("07") + "0300000000000000" + "20" + hex.EncodeToString(digest[:]) + // gas payment
+		address("05") + "e803000000000000" + "404b4c0000000000" + // gas owner, price and budget
+		"00") // no expiration
+	if !bytes.Equal(got, want) {
+		t.Fatalf("transaction encoded as\n%x, expected\n%x", got, want)
+	}
+
+	var decoded TransactionData
+	if err := bcs.Unmarshal(got, &decoded); err != nil || !reflect.DeepEqual(&decoded, data) {
+		t.Errorf("unexpected decoded transaction %+v, %v", decoded, err)
+	}
+
+	meta, err := b.BuildTxnMetaData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.TxBytes != base64.StdEncoding.EncodeToString(want) || len(meta.Gas) != 1 || meta.Gas[0].Digest != gasDigest {
+		t.Errorf("unexpected metadata %+v", meta)
+	}
+	txDigest, err := data.Digest()
+	if expected, _ := models.ComputeTransactionDigest(meta.TxBytes); err != nil || txDigest != expected {
+		t.Errorf("unexpected digest %s, %v", txDigest, err)
+	}
+	signed := meta.SignSerializedSigWith(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+	if signed.TxBytes != meta.TxBytes || signed.Signature == "" {
+		t.Errorf("unexpected signed transaction %+v", signed)
+	}
+}
+
+func TestBuildCommands(t *testing.T) {
+	b := newBuilder(t)
+	coin, _ := bcs.ParseObjectRef("0x11", 8, gasDigest)
+	pool := bcs.MustParseSuiAddress("0x12")
+	b.MergeCoins(b.OwnedObject(coin), b.OwnedObject(coin))
+	b.SharedObject(pool, 4, false)
+	shared := b.SharedObject(pool, 4, true)
+	vec := b.MakeMoveVec("0x2::coin::Coin<0x2::sui::SUI>", b.OwnedObject(coin))
+	swap := b.MoveCall("0xdee9::clob_v2::swap", []string{"0x2::sui::SUI", "0x2::coin::Coin<0x2::sui::SUI>"}, shared, vec, b.Pure(true))
+	upgradeCap := b.Publish([][]byte{{0xa1, 0x1c}}, []bcs.ObjectID{bcs.MustParseSuiAddress("0x1"), bcs.MustParseSuiAddress("0x2")})
+	ticket := b.MoveCall("0x2::package::authorize_upgrade", nil, upgradeCap, b.Pure(uint8(0)), b.PureBytes([]byte{0}))
+	receipt := b.Upgrade([][]byte{{0xa1, 0x1c}}, nil, bcs.MustParseSuiAddress("0x13"), ticket)
+	b.MoveCall("0x2::package::commit_upgrade", nil, upgradeCap, receipt)
+	b.TransferObjects([]Argument{swap.Nested(0), swap.Nested(1), upgradeCap}, b.PureAddress("0x5"))
+	data, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ptb := data.V1.Kind.ProgrammableTransaction
+	if len(ptb.Inputs) != 6 || len(ptb.Commands) != 8 {
+		t.Fatalf("unexpected %d inputs and %d commands", len(ptb.Inputs), len(ptb.Commands))
+	}
+	if shared := ptb.Inputs[1].Object.SharedObject; shared == nil || !shared.Mutable {
+		t.Errorf("expected the shared object to be added once, mutable, got %+v", ptb.Inputs[1])
+	}
+	call := ptb.Commands[2].MoveCall
+	if call == nil || call.Module != "clob_v2" || call.Function != "swap" || call.TypeArguments[1].String() != "0x2::coin::Coin<0x2::sui::SUI>" {
+		t.Errorf("unexpected move call %+v", call)
+	}
+	encoded, err := data.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded TransactionData
+	if err := bcs.Unmarshal(encoded, &decoded); err != nil || !reflect.DeepEqual(&decoded, data) {
+		t.Errorf("unexpected decoded transaction, %v", err)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	b := NewBuilder()
+	b.TransferObjects([]Argument{b.Gas()}, b.PureAddress("0x5"))
+	if _, err := b.Build(); !errors.Is(err, ErrMissingSender) {
+		t.Errorf("expected ErrMissingSender, got %v", err)
+	}
+	b.SetSender(bcs.MustParseSuiAddress("0x5"))
+	if _, err := b.Build(); !errors.Is(err, ErrMissingGasPayment) {
+		t.Errorf("expected ErrMissingGasPayment, got %v", err)
+	}
+	if _, err := b.BuildKind(); err != nil {
+		t.Errorf("expected the kind to build without gas data, got %v", err)
+	}
+
+	if _, err := newBuilder(t).Build(); !errors.Is(err, ErrNoCommands) {
+		t.Errorf("expected ErrNoCommands, got %v", err)
+	}
+
+	b = newBuilder(t)
+	b.MoveCall("0x2::coin", nil)
+	if _, err := b.Build(); err == nil || b.Err() == nil {
+		t.Error("expected an invalid target to fail the build")
+	}
+
+	b = newBuilder(t)
+	b.Pure(1)
+	if _, err := b.Build(); !errors.Is(err, bcs.ErrUnsupportedType) {
+		t.Errorf("expected an int to be rejected, got %v", err)
+	}
+
+	b = newBuilder(t)
+	b.MergeCoins(b.Gas(), b.Gas().Nested(0))
+	if _, err := b.Build(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected the nested result of the gas coin to be rejected, got %v", err)
+	}
+
+	b = newBuilder(t)
+	one := uint16(1)
+	b.TransferObjects([]Argument{{Result: &one}}, b.PureAddress("0x5"))
+	if _, err := b.Build(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected the result of a later command to be rejected, got %v", err)
+	}
+}
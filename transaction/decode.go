@@ -0,0 +1,59 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+)
+
+// SenderSignedTransaction is a signed transaction, as in the RawTransaction of a SuiTransactionBlockResponse.
+type SenderSignedTransaction struct {
+	IntentMessage IntentMessage
+	// Signatures are the `flag || signature || pubkey` of the signers.
+	Signatures [][]byte
+}
+
+// IntentMessage is the message signed: the transaction data and the intent it is signed with.
+type IntentMessage struct {
+	Intent Intent
+	Value  TransactionData
+}
+
+type Intent struct {
+	Scope   uint8
+	Version uint8
+	AppID   uint8
+}
+
+// DecodeTxBytes decodes base64 transaction data, such as the TxBytes of TxnMetaData returned by the `unsafe_*`
+// methods.
+func DecodeTxBytes(txBytes string) (*TransactionData, error) {
+	b, err := base64.StdEncoding.DecodeString(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx bytes: %w", err)
+	}
+	var data TransactionData
+	if err := bcs.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("invalid tx bytes: %w", err)
+	}
+	return &data, nil
+}
+
+// DecodeRawTransaction decodes the RawTransaction of a SuiTransactionBlockResponse, returned when ShowRawInput is
+// set: the transaction data and its signatures.
+func DecodeRawTransaction(rawTransaction string) (*SenderSignedTransaction, error) {
+	b, err := base64.StdEncoding.DecodeString(rawTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	// the signed data is a vector holding a single transaction
+	var txs []SenderSignedTransaction
+	if err := bcs.Unmarshal(b, &txs); err != nil {
+		return nil, fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	if len(txs) != 1 {
+		return nil, fmt.Errorf("invalid raw transaction: %d transactions", len(txs))
+	}
+	return &txs[0], nil
+}
@@ -0,0 +1,175 @@
+package transaction
+
+import (
+	"encoding/base64"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+// TransactionData is the data of a transaction, its BCS encoding is the TxBytes signed and executed.
+type TransactionData struct {
+	V1 *TransactionDataV1
+}
+
+func (TransactionData) IsBcsEnum() {}
+
+type TransactionDataV1 struct {
+	Kind       TransactionKind
+	Sender     bcs.SuiAddress
+	GasData    GasData
+	Expiration TransactionExpiration
+}
+
+// TransactionKind is the kind of a transaction. Only the programmable transactions are sent by the users, the
+// kinds of the system transactions are not supported.
+type TransactionKind struct {
+	ProgrammableTransaction *ProgrammableTransaction
+}
+
+func (TransactionKind) IsBcsEnum() {}
+
+// ProgrammableTransaction is a programmable transaction block: the commands and the inputs they use.
+type ProgrammableTransaction struct {
+	Inputs   []CallArg
+	Commands []Command
+}
+
+// GasData is the gas payment of a transaction: the coins paying for the gas, their owner, the price and budget.
+type GasData struct {
+	Payment []bcs.ObjectRef
+	Owner   bcs.SuiAddress
+	Price   uint64
+	Budget  uint64
+}
+
+// TransactionExpiration is the epoch after which the transaction can't be executed, if any.
+type TransactionExpiration struct {
+	None  *struct{}
+	Epoch *uint64
+}
+
+func (TransactionExpiration) IsBcsEnum() {}
+
+// CallArg is an input of a programmable transaction: a BCS encoded pure value or an object.
+type CallArg struct {
+	Pure   *[]byte
+	Object *ObjectArg
+}
+
+func (CallArg) IsBcsEnum() {}
+
+type ObjectArg struct {
+	ImmOrOwnedObject *bcs.ObjectRef
+	SharedObject     *SharedObject
+	Receiving        *bcs.ObjectRef
+}
+
+func (ObjectArg) IsBcsEnum() {}
+
+// SharedObject is a shared object input, referenced by the version it was shared at.
+type SharedObject struct {
+	ID                   bcs.ObjectID
+	InitialSharedVersion bcs.SequenceNumber
+	Mutable              bool
+}
+
+// Argument is an argument of a command: the gas coin, an input, the result of a previous command or one of its
+// results when it returns several.
+type Argument struct {
+	GasCoin      *struct{}
+	Input        *uint16
+	Result       *uint16
+	NestedResult *NestedResult
+}
+
+func (Argument) IsBcsEnum() {}
+
+type NestedResult struct {
+	Result uint16
+	Index  uint16
+}
+
+// Nested returns the i-th result of a command returning several, a is the result of the command.
+func (a Argument) Nested(i uint16) Argument {
+	if a.Result == nil {
+		return Argument{}
+	}
+	return Argument{NestedResult: &NestedResult{Result: *a.Result, Index: i}}
+}
+
+// Command is a command of a programmable transaction.
+type Command struct {
+	MoveCall        *ProgrammableMoveCall
+	TransferObjects *TransferObjects
+	SplitCoins      *SplitCoins
+	MergeCoins      *MergeCoins
+	Publish         *Publish
+	MakeMoveVec     *MakeMoveVec
+	Upgrade         *Upgrade
+}
+
+func (Command) IsBcsEnum() {}
+
+type ProgrammableMoveCall struct {
+	Package       bcs.ObjectID
+	Module        string
+	Function      string
+	TypeArguments []bcs.TypeTag
+	Arguments     []Argument
+}
+
+type TransferObjects struct {
+	Objects []Argument
+	Address Argument
+}
+
+type SplitCoins struct {
+	Coin    Argument
+	Amounts []Argument
+}
+
+type MergeCoins struct {
+	Destination Argument
+	Sources     []Argument
+}
+
+type Publish struct {
+	Modules      [][]byte
+	Dependencies []bcs.ObjectID
+}
+
+type MakeMoveVec struct {
+	Type     *bcs.TypeTag
+	Elements []Argument
+}
+
+type Upgrade struct {
+	Modules      [][]byte
+	Dependencies []bcs.ObjectID
+	Package      bcs.ObjectID
+	Ticket       Argument
+}
+
+// Bytes returns the BCS encoding of the transaction data.
+func (t *TransactionData) Bytes() ([]byte, error) {
+	return bcs.Marshal(t)
+}
+
+// TxBytes returns the base64 BCS encoding of the transaction data, as expected by `sui_executeTransactionBlock`.
+func (t *TransactionData) TxBytes() (string, error) {
+	b, err := t.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Digest returns the digest the transaction is known by once executed.
+func (t *TransactionData) Digest() (models.TransactionDigest, error) {
+	txBytes, err := t.TxBytes()
+	if err != nil {
+		return "", err
+	}
+	return models.ComputeTransactionDigest(txBytes)
+}
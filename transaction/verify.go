@@ -0,0 +1,348 @@
+package transaction
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+var (
+	ErrNotProgrammable       = errors.New("transaction is not a programmable transaction")
+	ErrUnexpectedTransaction = errors.New("transaction doesn't match the request")
+)
+
+// Transfer is an object transferred by a transaction.
+type Transfer struct {
+	Recipient bcs.SuiAddress
+	// Amount is the amount of the coin when it is split off by the transaction, nil otherwise.
+	Amount *uint64
+	// Object is the id of the object when it is an input of the transaction, nil otherwise.
+	Object *bcs.ObjectID
+	// GasCoin is set when the object is the gas coin.
+	GasCoin bool
+}
+
+func (t *TransactionData) programmable() (*ProgrammableTransaction, error) {
+	if t.V1 == nil || t.V1.Kind.ProgrammableTransaction == nil {
+		return nil, ErrNotProgrammable
+	}
+	return t.V1.Kind.ProgrammableTransaction, nil
+}
+
+// Transfers returns the objects transferred by the `TransferObjects` commands of the transaction, and their
+// recipient. A recipient that isn't a pure address input is an error, as it can't be checked.
+func (t *TransactionData) Transfers() ([]Transfer, error) {
+	ptb, err := t.programmable()
+	if err != nil {
+		return nil, err
+	}
+	amounts := make(map[NestedResult]uint64)
+	var transfers []Transfer
+	for i, cmd := range ptb.Commands {
+		switch {
+		case cmd.SplitCoins != nil:
+			for j, amount := range cmd.SplitCoins.Amounts {
+				if v, ok := pureU64(ptb, amount); ok {
+					amounts[NestedResult{Result: uint16(i), Index: uint16(j)}] = v
+				}
+			}
+		case cmd.TransferObjects != nil:
+			recipient, ok := pureAddress(ptb, cmd.TransferObjects.Address)
+			if !ok {
+				return nil, fmt.Errorf("command %d: recipient is not a pure address", i)
+			}
+			for _, obj := range cmd.TransferObjects.Objects {
+				transfer := Transfer{Recipient: recipient, GasCoin: obj.GasCoin != nil}
+				if obj.NestedResult != nil {
+					if amount, ok := amounts[*obj.NestedResult]; ok {
+						transfer.Amount = &amount
+					}
+				}
+				if input := inputOf(ptb, obj); input != nil && input.Object != nil {
+					if id, ok := objectID(*input.Object); ok {
+						transfer.Object = &id
+					}
+				}
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+	return transfers, nil
+}
+
+// inputOf returns the input arg is, nil if it isn't an input.
+func inputOf(ptb *ProgrammableTransaction, arg Argument) *CallArg {
+	if arg.Input == nil || int(*arg.Input) >= len(ptb.Inputs) {
+		return nil
+	}
+	return &ptb.Inputs[*arg.Input]
+}
+
+func pureU64(ptb *ProgrammableTransaction, arg Argument) (uint64, bool) {
+	input := inputOf(ptb, arg)
+	if input == nil || input.Pure == nil || len(*input.Pure) != 8 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(*input.Pure), true
+}
+
+func pureAddress(ptb *ProgrammableTransaction, arg Argument) (bcs.SuiAddress, bool) {
+	var addr bcs.SuiAddress
+	input := inputOf(ptb, arg)
+	if input == nil || input.Pure == nil || len(*input.Pure) != bcs.SuiAddressLength {
+		return addr, false
+	}
+	copy(addr[:], *input.Pure)
+	return addr, true
+}
+
+// Expectation is what a transaction is expected to do, checked by Verify before signing it.
+type Expectation struct {
+	// Sender is the address expected to send the transaction, not checked if empty.
+	Sender string
+	// GasBudget is the gas budget expected, not checked if 0.
+	GasBudget uint64
+	// GasOwner is the address expected to own the gas coins, the Sender if empty. Not checked if both are empty.
+	GasOwner string
+	// GasPayment are the ids of the coins the gas may be paid with, not checked if empty.
+	GasPayment []string
+	// Transfers are the transfers expected, in any order, and no other. The transaction may then only split, merge
+	// and transfer coins, any other command could move them. Not checked if nil.
+	Transfers []ExpectedTransfer
+}
+
+// ExpectedTransfer is a transfer expected. With an ObjectID the object is expected, with an Amount a coin of that
+// amount split off by the transaction, and with neither the gas coin.
+type ExpectedTransfer struct {
+	Recipient string
+	Amount    *uint64
+	ObjectID  string
+}
+
+// VerifyTxBytes decodes base64 transaction data and checks it matches expected.
+func VerifyTxBytes(txBytes string, expected Expectation) (*TransactionData, error) {
+	data, err := DecodeTxBytes(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	return data, data.Verify(expected)
+}
+
+// Verify checks the transaction matches expected, and returns an ErrUnexpectedTransaction otherwise.
+func (t *TransactionData) Verify(expected Expectation) error {
+	if _, err := t.programmable(); err != nil {
+		return err
+	}
+	if expected.Sender != "" {
+		sender, err := bcs.ParseSuiAddress(expected.Sender)
+		if err != nil {
+			return err
+		}
+		if t.V1.Sender != sender {
+			return fmt.Errorf("%w: sender is %s, expected %s", ErrUnexpectedTransaction, t.V1.Sender, sender)
+		}
+	}
+	if expected.GasBudget != 0 && t.V1.GasData.Budget != expected.GasBudget {
+		return fmt.Errorf("%w: gas budget is %d, expected %d", ErrUnexpectedTransaction, t.V1.GasData.Budget, expected.GasBudget)
+	}
+	if err := t.verifyGas(expected); err != nil {
+		return err
+	}
+	if expected.Transfers == nil {
+		return nil
+	}
+	for i, cmd := range t.V1.Kind.ProgrammableTransaction.Commands {
+		if cmd.SplitCoins == nil && cmd.MergeCoins == nil && cmd.TransferObjects == nil {
+			return fmt.Errorf("%w: command %d doesn't split, merge nor transfer coins", ErrUnexpectedTransaction, i)
+		}
+	}
+
+	transfers, err := t.Transfers()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnexpectedTransaction, err)
+	}
+	unmatched := make([]bool, len(transfers))
+	for i := range unmatched {
+		unmatched[i] = true
+	}
+	for _, want := range expected.Transfers {
+		recipient, err := bcs.ParseSuiAddress(want.Recipient)
+		if err != nil {
+			return err
+		}
+		var objectID *bcs.ObjectID
+		if want.ObjectID != "" {
+			id, err := bcs.ParseSuiAddress(want.ObjectID)
+			if err != nil {
+				return err
+			}
+			objectID = &id
+		}
+		found := false
+		for i, transfer := range transfers {
+			if unmatched[i] && transfer.Recipient == recipient && transfer.matches(want.Amount, objectID) {
+				unmatched[i], found = false, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: no transfer of %s to %s", ErrUnexpectedTransaction, want.describe(), recipient)
+		}
+	}
+	for i, transfer := range transfers {
+		if unmatched[i] {
+			return fmt.Errorf("%w: unexpected transfer to %s", ErrUnexpectedTransaction, transfer.Recipient)
+		}
+	}
+	return nil
+}
+
+// verifyGas checks the owner and the coins of the gas payment.
+func (t *TransactionData) verifyGas(expected Expectation) error {
+	gas := t.V1.GasData
+	owner := expected.GasOwner
+	if owner == "" {
+		owner = expected.Sender
+	}
+	if owner != "" {
+		addr, err := bcs.ParseSuiAddress(owner)
+		if err != nil {
+			return err
+		}
+		if gas.Owner != addr {
+			return fmt.Errorf("%w: gas owner is %s, expected %s", ErrUnexpectedTransaction, gas.Owner, addr)
+		}
+	}
+	if len(expected.GasPayment) == 0 {
+		return nil
+	}
+	allowed := make(map[bcs.ObjectID]bool, len(expected.GasPayment))
+	for _, id := range expected.GasPayment {
+		addr, err := bcs.ParseSuiAddress(id)
+		if err != nil {
+			return err
+		}
+		allowed[addr] = true
+	}
+	for _, coin := range gas.Payment {
+		if !allowed[coin.ObjectID] {
+			return fmt.Errorf("%w: unexpected gas coin %s", ErrUnexpectedTransaction, coin.ObjectID)
+		}
+	}
+	return nil
+}
+
+func (t Transfer) matches(amount *uint64, objectID *bcs.ObjectID) bool {
+	switch {
+	case objectID != nil:
+		return t.Object != nil && *t.Object == *objectID
+	case amount != nil:
+		return t.Amount != nil && *t.Amount == *amount
+	default:
+		return t.GasCoin
+	}
+}
+
+func (e ExpectedTransfer) describe() string {
+	switch {
+	case e.ObjectID != "":
+		return "object " + e.ObjectID
+	case e.Amount != nil:
+		return strconv.FormatUint(*e.Amount, 10) + " MIST"
+	default:
+		return "the gas coin"
+	}
+}
+
+func parseUint(name, s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, s, err)
+	}
+	return n, nil
+}
+
+// ExpectTransferSui returns the expectation of the transaction built by TransferSui for req.
+func ExpectTransferSui(req models.TransferSuiRequest) (Expectation, error) {
+	budget, err := parseUint("gas budget", req.GasBudget)
+	if err != nil {
+		return Expectation{}, err
+	}
+	transfer := ExpectedTransfer{Recipient: req.Recipient}
+	if req.Amount != "" {
+		amount, err := parseUint("amount", req.Amount)
+		if err != nil {
+			return Expectation{}, err
+		}
+		transfer.Amount = &amount
+	}
+	return Expectation{Sender: req.Signer, GasBudget: budget, GasPayment: gasPayment(req.SuiObjectId), Transfers: []ExpectedTransfer{transfer}}, nil
+}
+
+// ExpectTransferObject returns the expectation of the transaction built by TransferObject for req.
+func ExpectTransferObject(req models.TransferObjectRequest) (Expectation, error) {
+	budget, err := parseUint("gas budget", req.GasBudget)
+	if err != nil {
+		return Expectation{}, err
+	}
+	return Expectation{
+		Sender:     req.Signer,
+		GasBudget:  budget,
+		GasPayment: gasPayment(req.Gas),
+		Transfers:  []ExpectedTransfer{{Recipient: req.Recipient, ObjectID: req.ObjectId}},
+	}, nil
+}
+
+// ExpectPay returns the expectation of the transaction built by Pay for req.
+func ExpectPay(req models.PayRequest) (Expectation, error) {
+	return expectPayments(req.Signer, req.GasBudget, gasPayment(req.Gas), req.Recipient, req.Amount)
+}
+
+// ExpectPaySui returns the expectation of the transaction built by PaySui for req, its coins pay for the gas.
+func ExpectPaySui(req models.PaySuiRequest) (Expectation, error) {
+	return expectPayments(req.Signer, req.GasBudget, req.SuiObjectId, req.Recipient, req.Amount)
+}
+
+// ExpectPayAllSui returns the expectation of the transaction built by PayAllSui for req, the gas coin is
+// transferred once the coins are merged into it.
+func ExpectPayAllSui(req models.PayAllSuiRequest) (Expectation, error) {
+	budget, err := parseUint("gas budget", req.GasBudget)
+	if err != nil {
+		return Expectation{}, err
+	}
+	return Expectation{Sender: req.Signer, GasBudget: budget, GasPayment: req.SuiObjectId, Transfers: []ExpectedTransfer{{Recipient: req.Recipient}}}, nil
+}
+
+// gasPayment returns the gas coin of a request, nil if the node picks it.
+func gasPayment(gas string) []string {
+	if gas == "" {
+		return nil
+	}
+	return []string{gas}
+}
+
+func expectPayments(signer, gasBudget string, payment, recipients, amounts []string) (Expectation, error) {
+	budget, err := parseUint("gas budget", gasBudget)
+	if err != nil {
+		return Expectation{}, err
+	}
+	if len(recipients) != len(amounts) {
+		return Expectation{}, fmt.Errorf("%d recipients for %d amounts", len(recipients), len(amounts))
+	}
+	expected := Expectation{Sender: signer, GasBudget: budget, GasPayment: payment, Transfers: make([]ExpectedTransfer, len(recipients))}
+	for i, recipient := range recipients {
+		amount, err := parseUint("amount", amounts[i])
+		if err != nil {
+			return Expectation{}, err
+		}
+		expected.Transfers[i] = ExpectedTransfer{Recipient: recipient, Amount: &amount}
+	}
+	return expected, nil
+}
@@ -0,0 +1,168 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yasir7ca/sui-go-sdk/bcs"
+	"github.com/yasir7ca/sui-go-sdk/models"
+)
+
+const (
+	sender    = "0x5"
+	recipient = "0x9"
+	other     = "0xa"
+)
+
+// payTxBytes builds the transaction `unsafe_pay` builds for two payments to recipient and one to other.
+func payTxBytes(t *testing.T, budget uint64, amounts ...uint64) string {
+	b := newBuilder(t).SetGasBudget(budget)
+	coin, _ := bcs.ParseObjectRef("0x11", 8, gasDigest)
+	var pure []Argument
+	for _, amount := range amounts {
+		pure = append(pure, b.Pure(amount))
+	}
+	coins := b.SplitCoins(b.OwnedObject(coin), pure...)
+	b.TransferObjects(coins[:2], b.PureAddress(recipient))
+	b.TransferObjects(coins[2:], b.PureAddress(other))
+	data, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := data.TxBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txBytes
+}
+
+func TestDecode(t *testing.T) {
+	txBytes := payTxBytes(t, 5000000, 1, 2, 3)
+	data, err := DecodeTxBytes(txBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.V1.Sender != bcs.MustParseSuiAddress(sender) || data.V1.GasData.Price != 1000 || data.V1.Expiration.None == nil {
+		t.Errorf("unexpected transaction %+v", data.V1)
+	}
+	if cmds := data.V1.Kind.ProgrammableTransaction.Commands; len(cmds) != 3 || cmds[0].SplitCoins == nil {
+		t.Errorf("unexpected commands %+v", cmds)
+	}
+
+	b, _ := base64.StdEncoding.DecodeString(txBytes)
+	raw := append([]byte{1, 0, 0, 0}, b...)
+	raw = append(raw, 1, 2, 0xab, 0xcd)
+	signed, err := DecodeRawTransaction(base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&signed.IntentMessage.Value, data) || len(signed.Signatures) != 1 || len(signed.Signatures[0]) != 2 {
+		t.Errorf("unexpected signed transaction %+v", signed)
+	}
+
+	if _, err := DecodeTxBytes(base64.StdEncoding.EncodeToString(append(b, 0))); !errors.Is(err, bcs.ErrTrailingBytes) {
+		t.Errorf("expected ErrTrailingBytes, got %v", err)
+	}
+	if _, err := DecodeTxBytes("not base64"); err == nil {
+		t.Error("expected invalid base64 to be rejected")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	req := models.PayRequest{
+		Signer:    sender,
+		Recipient: []string{other, recipient, recipient},
+		Amount:    []string{"3", "1", "2"},
+		GasBudget: "5000000",
+	}
+	expected, err := ExpectPay(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyTxBytes(payTxBytes(t, 5000000, 1, 2, 3), expected); err != nil {
+		t.Errorf("expected the transaction to match the request, got %v", err)
+	}
+
+	mismatches := map[string]string{
+		"budget":           payTxBytes(t, 9000000, 1, 2, 3),
+		"amount":           payTxBytes(t, 5000000, 1, 2, 4),
+		"extra transfer":   payTxBytes(t, 5000000, 1, 2, 3, 4),
+		"missing transfer": payTxBytes(t, 5000000, 1, 2),
+	}
+	for name, txBytes := range mismatches {
+		if _, err := VerifyTxBytes(txBytes, expected); !errors.Is(err, ErrUnexpectedTransaction) {
+			t.Errorf("%s: expected ErrUnexpectedTransaction, got %v", name, err)
+		}
+	}
+	if _, err := VerifyTxBytes(payTxBytes(t, 5000000, 1, 2, 3), Expectation{Sender: other}); !errors.Is(err, ErrUnexpectedTransaction) {
+		t.Errorf("expected the sender to be checked, got %v", err)
+	}
+
+	b := newBuilder(t)
+	b.TransferObjects([]Argument{b.Gas()}, b.PureAddress(recipient))
+	data, _ := b.Build()
+	expected, _ = ExpectTransferSui(models.TransferSuiRequest{Signer: sender, Recipient: recipient, GasBudget: "5000000"})
+	if err := data.Verify(expected); err != nil {
+		t.Errorf("expected the transfer of the gas coin to match, got %v", err)
+	}
+
+	b = newBuilder(t)
+	object, _ := bcs.ParseObjectRef("0x11", 8, gasDigest)
+	b.TransferObjects([]Argument{b.OwnedObject(object)}, b.PureAddress(recipient))
+	data, _ = b.Build()
+	expected, _ = ExpectTransferObject(models.TransferObjectRequest{Signer: sender, ObjectId: "0x11", Recipient: recipient})
+	if err := data.Verify(expected); err != nil {
+		t.Errorf("expected the transfer of the object to match, got %v", err)
+	}
+	expected.Transfers[0].ObjectID = "0x12"
+	if err := data.Verify(expected); !errors.Is(err, ErrUnexpectedTransaction) {
+		t.Errorf("expected the object to be checked, got %v", err)
+	}
+}
+
+func TestVerifyRejectsOtherCommands(t *testing.T) {
+	expected, _ := ExpectTransferSui(models.TransferSuiRequest{Signer: sender, SuiObjectId: "0x7", Recipient: recipient, Amount: "100"})
+	build := func(inject func(b *Builder)) *TransactionData {
+		b := newBuilder(t)
+		b.TransferObjects(b.SplitCoins(b.Gas(), b.Pure(uint64(100))), b.PureAddress(recipient))
+		inject(b)
+		data, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	if err := build(func(*Builder) {}).Verify(expected); err != nil {
+		t.Fatalf("expected the transfer to match, got %v", err)
+	}
+
+	injected := map[string]func(b *Builder){
+		"move call transfer": func(b *Builder) {
+			b.MoveCall("0x2::pay::split_and_transfer", []string{"0x2::sui::SUI"}, b.Gas(), b.Pure(uint64(1000)), b.PureAddress(other))
+		},
+		"move vector": func(b *Builder) {
+			b.MakeMoveVec("0x2::coin::Coin<0x2::sui::SUI>", b.SplitCoins(b.Gas(), b.Pure(uint64(1)))...)
+		},
+		"gas owner": func(b *Builder) {
+			b.SetGasOwner(bcs.MustParseSuiAddress(other))
+		},
+		"gas payment": func(b *Builder) {
+			coin, _ := bcs.ParseObjectRef("0x11", 8, gasDigest)
+			b.SetGasPayment(coin)
+		},
+	}
+	for name, inject := range injected {
+		if err := build(inject).Verify(expected); !errors.Is(err, ErrUnexpectedTransaction) {
+			t.Errorf("%s: expected ErrUnexpectedTransaction, got %v", name, err)
+		}
+	}
+
+	// a sponsor pays for the gas
+	sponsored := build(func(b *Builder) { b.SetGasOwner(bcs.MustParseSuiAddress(other)) })
+	expected.GasOwner = other
+	if err := sponsored.Verify(expected); err != nil {
+		t.Errorf("expected the sponsored transaction to match, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var ErrInvalidBase58 = errors.New("invalid base58 string")
+
+var base58Indexes = func() [256]int {
+	var indexes [256]int
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for i, c := range base58Alphabet {
+		indexes[c] = i
+	}
+	return indexes
+}()
+
+// Base58Encode encodes data with the Bitcoin base58 alphabet, which Sui uses for digests.
+func Base58Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Base58Decode decodes a string encoded with the Bitcoin base58 alphabet.
+func Base58Decode(str string) ([]byte, error) {
+	x := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(str); i++ {
+		index := base58Indexes[str[i]]
+		if index < 0 {
+			return nil, ErrInvalidBase58
+		}
+		x.Mul(x, radix)
+		x.Add(x, big.NewInt(int64(index)))
+	}
+
+	decoded := x.Bytes()
+	zeros := 0
+	for zeros < len(str) && str[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	out := make([]byte, zeros+len(decoded))
+	copy(out[zeros:], decoded)
+	return out, nil
+}
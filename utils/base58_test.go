@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		encoded string
+	}{
+		{nil, ""},
+		{[]byte("hello world"), "StV1DL6CwTryKyV"},
+		{[]byte{0, 0, 1}, "112"},
+		{[]byte{0xff, 0xff}, "LUv"},
+	}
+	for _, tt := range tests {
+		if encoded := Base58Encode(tt.data); encoded != tt.encoded {
+			t.Errorf("expected %x to encode to %q, got %q", tt.data, tt.encoded, encoded)
+		}
+		decoded, err := Base58Decode(tt.encoded)
+		if err != nil || !bytes.Equal(decoded, tt.data) {
+			t.Errorf("expected %q to decode to %x, got %x, %v", tt.encoded, tt.data, decoded, err)
+		}
+	}
+	for _, invalid := range []string{"0", "O", "I", "l", "abc+"} {
+		if _, err := Base58Decode(invalid); !errors.Is(err, ErrInvalidBase58) {
+			t.Errorf("expected %q to be rejected, got %v", invalid, err)
+		}
+	}
+}